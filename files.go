@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// Files checked when walking a directory if no --include is given
+var defaultInclude = []string{"*.json"}
+
+// Expands the paths given with --file into the list of files to read
+type fileFinder struct {
+	recursive bool
+	include   []string
+	exclude   []string
+}
+
+// Returns the files named by paths in order, without duplicates. Each path can be a file,
+// a glob or a directory. Directories are searched for files matching the include patterns,
+// along with their subdirectories if recursive is set. Files and directories matching an
+// exclude pattern are skipped, apart from files named directly. Paths that don't exist are
+// returned as they are so the error is reported when they are opened
+func (f *fileFinder) find(paths []string) ([]string, error) {
+	var files []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	for _, path := range paths {
+		if !isGlob(path) {
+			if err := f.walk(path, add, false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid glob %s: %w", path, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("No files match %s", path)
+		}
+		for _, match := range matches {
+			if err := f.walk(match, add, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// Adds path if it is a file, or the files under it if it is a directory.
+// matched is set when path came from a glob rather than being named directly
+func (f *fileFinder) walk(root string, add func(string), matched bool) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				add(path)
+				return nil
+			}
+			return err
+		}
+		if path == root {
+			if !entry.IsDir() && !(matched && matchesAny(f.exclude, path)) {
+				add(path)
+			}
+			return nil
+		}
+		if matchesAny(f.exclude, path) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if !f.recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		include := f.include
+		if len(include) == 0 {
+			include = defaultInclude
+		}
+		if matchesAny(include, path) {
+			add(path)
+		}
+		return nil
+	})
+}
+
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// Reports whether path or its base name matches one of patterns
+func matchesAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+	}
+	return false
+}
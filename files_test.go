@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"json-parser/jsonparser"
+)
+
+// Creates the files, given relative to a new temporary directory, and returns the directory
+func makeTree(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFind(t *testing.T) {
+
+	root := makeTree(t, map[string]string{
+		"a.json":          "{}",
+		"b.json":          "{}",
+		"notes.txt":       "",
+		"skip.json":       "{}",
+		"sub/c.json":      "{}",
+		"sub/deep/d.json": "{}",
+		"vendor/e.json":   "{}",
+	})
+	in := func(name string) string {
+		return filepath.Join(root, filepath.FromSlash(name))
+	}
+	tests := []struct {
+		name     string
+		finder   fileFinder
+		paths    []string
+		expected []string
+	}{
+		{"directory", fileFinder{}, []string{root}, []string{"a.json", "b.json", "skip.json"}},
+		{"recursive", fileFinder{recursive: true}, []string{root},
+			[]string{"a.json", "b.json", "skip.json", "sub/c.json", "sub/deep/d.json", "vendor/e.json"}},
+		{"exclude", fileFinder{recursive: true, exclude: []string{"vendor", "skip.json", in("sub/deep")}}, []string{root},
+			[]string{"a.json", "b.json", "sub/c.json"}},
+		{"include", fileFinder{include: []string{"*.txt"}}, []string{root}, []string{"notes.txt"}},
+		{"named directly", fileFinder{exclude: []string{"skip.json"}}, []string{in("skip.json")}, []string{"skip.json"}},
+		{"glob", fileFinder{exclude: []string{"skip.json"}}, []string{in("*.json")}, []string{"a.json", "b.json"}},
+		{"glob of directories", fileFinder{}, []string{in("s*")}, []string{"skip.json", "sub/c.json"}},
+		{"duplicates", fileFinder{}, []string{in("b.json"), in("*.json"), root}, []string{"b.json", "a.json", "skip.json"}},
+		{"missing", fileFinder{}, []string{in("missing.json")}, []string{"missing.json"}},
+	}
+	for _, test := range tests {
+		files, err := test.finder.find(test.paths)
+		if err != nil {
+			t.Errorf("%s: Expected no error, Got : %s", test.name, err)
+			continue
+		}
+		got := []string{}
+		for _, file := range files {
+			rel, _ := filepath.Rel(root, file)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		if strings.Join(got, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("%s: Expected %v, Got : %v", test.name, test.expected, got)
+		}
+	}
+
+	finder := fileFinder{}
+	if _, err := finder.find([]string{in("*.yaml")}); err == nil || err.Error() != "No files match "+in("*.yaml") {
+		t.Errorf("Expected No files match, Got : %v", err)
+	}
+	if _, err := finder.find([]string{in("[")}); err == nil || !strings.HasPrefix(err.Error(), "Invalid glob") {
+		t.Errorf("Expected Invalid glob, Got : %v", err)
+	}
+}
+func TestValidateFilesKeepsOrder(t *testing.T) {
+
+	files := map[string]string{}
+	var paths []string
+	for i := range 40 {
+		name := filepath.Join("dir", strings.Repeat("x", i+1)+".json")
+		files[name] = "[1, 2]"
+		if i%3 == 0 {
+			files[name] = "[1, 2"
+		}
+		paths = append(paths, name)
+	}
+	root := makeTree(t, files)
+	for i := range paths {
+		paths[i] = filepath.Join(root, paths[i])
+	}
+	paths = append(paths, filepath.Join(root, "missing.json"))
+
+	var results []fileResult
+	validateFiles(jsonparser.Options{}, paths, 8, func(r fileResult) {
+		results = append(results, r)
+	})
+	if len(results) != len(paths) {
+		t.Fatalf("Expected %d results, Got : %d", len(paths), len(results))
+	}
+	for i, r := range results {
+		if r.file != paths[i] {
+			t.Errorf("Expected result %d to be for %s, Got : %s", i, paths[i], r.file)
+		}
+		expected := exitValid
+		switch {
+		case i == len(paths)-1:
+			expected = exitUsage
+		case i%3 == 0:
+			expected = exitInvalid
+		}
+		if code := exitCode(r.err); code != expected {
+			t.Errorf("%s: Expected exit code %d, Got : %d %v", r.file, expected, code, r.err)
+		}
+	}
+}
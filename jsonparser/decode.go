@@ -0,0 +1,386 @@
+package jsonparser
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// Parses data with the default options and stores the result in the value pointed to by v
+func Unmarshal(data []byte, v any) error {
+	return Options{}.Unmarshal(data, v)
+}
+func (o Options) Unmarshal(data []byte, v any) error {
+	value, err := o.ParseReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return value.Decode(v)
+}
+
+// Stores the value in the Go value pointed to by target, following the same rules as encoding/json.Unmarshal:
+//
+//   - objects go into structs, using the field name or its `json:"name"` tag, or into maps with
+//     string, integer or encoding.TextUnmarshaler keys
+//   - arrays go into slices and arrays, and extra elements are dropped when an array is too short
+//   - null sets pointers, maps, slices and interfaces to nil and leaves anything else as it is
+//   - nil pointers are allocated, including pointers to embedded structs
+//   - an empty interface gets the same values as Value.Interface returns
+//   - strings are passed to UnmarshalText when the target implements encoding.TextUnmarshaler
+//   - a base64 string goes into a []byte
+//   - numbers are converted from the exact text of the input, so 64-bit integers keep every digit,
+//     and can be kept as they are in a Number
+func (v *Value) Decode(target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("Error Decoding JSON. Expected a non-nil pointer but got %T", target)
+	}
+	return decodeValue(v, rv.Elem())
+}
+
+func decodeValue(v *Value, rv reflect.Value) error {
+	if v.Kind == NullValue {
+		switch rv.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			rv.SetZero()
+		}
+		return nil
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	if v.Kind == StringValue && rv.CanAddr() {
+		if unmarshaler, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := unmarshaler.UnmarshalText([]byte(v.Str)); err != nil {
+				return decodeError(v, err.Error())
+			}
+			return nil
+		}
+	}
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		rv.Set(reflect.ValueOf(v.Interface()))
+		return nil
+	}
+	switch v.Kind {
+	case BoolValue:
+		if rv.Kind() != reflect.Bool {
+			return decodeTypeError(v, rv)
+		}
+		rv.SetBool(v.Bool)
+	case NumberValue:
+		return decodeNumber(v, rv)
+	case StringValue:
+		if isByteSlice(rv.Type()) {
+			data, err := base64.StdEncoding.DecodeString(v.Str)
+			if err != nil {
+				return decodeError(v, fmt.Sprintf("Invalid base64 for %s: %s", rv.Type(), err))
+			}
+			rv.SetBytes(data)
+			return nil
+		}
+		if rv.Kind() != reflect.String {
+			return decodeTypeError(v, rv)
+		}
+		rv.SetString(v.Str)
+	case ArrayValue:
+		return decodeArray(v, rv)
+	case ObjectValue:
+		return decodeObject(v, rv)
+	}
+	return nil
+}
+
+func decodeNumber(v *Value, rv reflect.Value) error {
+	number := v.number()
+	if rv.Type() == numberType {
+		rv.SetString(string(number))
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := number.Int64()
+		if errors.Is(err, ErrPrecisionLoss) {
+			return decodeTypeError(v, rv)
+		}
+		if err != nil || rv.OverflowInt(i) {
+			return decodeError(v, fmt.Sprintf("Number %s overflows %s", number, rv.Type()))
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := number.Uint64()
+		if errors.Is(err, ErrPrecisionLoss) || (err != nil && strings.HasPrefix(string(number), "-")) {
+			return decodeTypeError(v, rv)
+		}
+		if err != nil || rv.OverflowUint(u) {
+			return decodeError(v, fmt.Sprintf("Number %s overflows %s", number, rv.Type()))
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		// rounding to the nearest float is expected here
+		f, err := number.Float64()
+		if errors.Is(err, ErrOverflow) || rv.OverflowFloat(f) {
+			return decodeError(v, fmt.Sprintf("Number %s overflows %s", number, rv.Type()))
+		}
+		rv.SetFloat(f)
+	default:
+		return decodeTypeError(v, rv)
+	}
+	return nil
+}
+
+func decodeArray(v *Value, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(rv.Type(), len(v.Elements), len(v.Elements))
+		for i, element := range v.Elements {
+			if err := decodeValue(element, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(slice)
+	case reflect.Array:
+		for i := range rv.Len() {
+			if i >= len(v.Elements) {
+				rv.Index(i).SetZero()
+				continue
+			}
+			if err := decodeValue(v.Elements[i], rv.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return decodeTypeError(v, rv)
+	}
+	return nil
+}
+
+func decodeObject(v *Value, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Map:
+		if !decodableMapKey(rv.Type().Key()) {
+			return decodeTypeError(v, rv)
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(v.Members)))
+		}
+		for _, member := range v.Members {
+			key, err := decodeMapKey(member.Key, rv.Type().Key())
+			if err != nil {
+				return decodeError(v, err.Error())
+			}
+			element := reflect.New(rv.Type().Elem()).Elem()
+			if err := decodeValue(member.Value, element); err != nil {
+				return err
+			}
+			rv.SetMapIndex(key, element)
+		}
+	case reflect.Struct:
+		fields := structFields(rv.Type())
+		for _, member := range v.Members {
+			field, ok := findField(fields, member.Key)
+			if !ok {
+				continue // members without a matching field are ignored
+			}
+			fieldValue, err := fieldByIndex(rv, field.index)
+			if err != nil {
+				return decodeError(member.Value, err.Error())
+			}
+			if err := decodeValue(member.Value, fieldValue); err != nil {
+				return err
+			}
+		}
+	default:
+		return decodeTypeError(v, rv)
+	}
+	return nil
+}
+
+// Reports whether object keys can be decoded into map keys of type t
+func decodableMapKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// Converts an object key to a map key of type t, the reverse of what mapKey does when encoding
+func decodeMapKey(key string, t reflect.Type) (reflect.Value, error) {
+	if t.Kind() == reflect.String {
+		return reflect.ValueOf(key).Convert(t), nil
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		rv := reflect.New(t)
+		if err := rv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, err
+		}
+		return rv.Elem(), nil
+	}
+	rv := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, 64)
+		if err == nil && !rv.OverflowInt(i) {
+			rv.SetInt(i)
+			return rv, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(key, 10, 64)
+		if err == nil && !rv.OverflowUint(u) {
+			rv.SetUint(u)
+			return rv, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("Cannot decode key %q into Go value of type %s", key, t)
+}
+
+// A struct field that an object member maps to
+type field struct {
+	name      string
+	index     []int // path to the field through any embedded structs
+	tagged    bool
+	omitEmpty bool
+}
+
+var fieldCache sync.Map // reflect.Type -> []field
+
+// Returns the fields of struct type t in declaration order, including those promoted from embedded structs.
+// As with encoding/json a field hides fields of the same name nested deeper, and fields of the same
+// name at the same depth hide each other unless exactly one of them is tagged
+func structFields(t reflect.Type) []field {
+	if fields, ok := fieldCache.Load(t); ok {
+		return fields.([]field)
+	}
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	fields := []field{}
+	taken := map[string]bool{}
+	visited := map[reflect.Type]bool{}
+	for current := []embedded{{t, nil}}; len(current) > 0; {
+		next := []embedded{}
+		candidates := map[string][]field{}
+		names := []string{}
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := range e.typ.NumField() {
+				structField := e.typ.Field(i)
+				tag := structField.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, options, _ := strings.Cut(tag, ",")
+				index := append(slices.Clone(e.index), i)
+				fieldType := structField.Type
+				if fieldType.Kind() == reflect.Pointer {
+					fieldType = fieldType.Elem()
+				}
+				if structField.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+					next = append(next, embedded{fieldType, index})
+					continue
+				}
+				if !structField.IsExported() {
+					continue
+				}
+				f := field{name: name, index: index, tagged: name != "", omitEmpty: slices.Contains(strings.Split(options, ","), "omitempty")}
+				if f.name == "" {
+					f.name = structField.Name
+				}
+				if _, ok := candidates[f.name]; !ok {
+					names = append(names, f.name)
+				}
+				candidates[f.name] = append(candidates[f.name], f)
+			}
+		}
+		for _, name := range names {
+			if taken[name] {
+				continue
+			}
+			taken[name] = true
+			if dominant, ok := dominantField(candidates[name]); ok {
+				fields = append(fields, dominant)
+			}
+		}
+		current = next
+	}
+	slices.SortFunc(fields, func(a, b field) int {
+		return slices.Compare(a.index, b.index)
+	})
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// Picks the field that wins out of several with the same name at the same depth
+func dominantField(fields []field) (field, bool) {
+	if len(fields) == 1 {
+		return fields[0], true
+	}
+	tagged := []field{}
+	for _, f := range fields {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return field{}, false
+}
+
+// Finds the field for an object key, preferring an exact match over a case-insensitive one
+func findField(fields []field, key string) (field, bool) {
+	for _, f := range fields {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// Returns the field at index, allocating any nil embedded struct pointers on the way
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, error) {
+	for i, fieldIndex := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("Cannot set embedded pointer to unexported struct %s", rv.Type().Elem())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(fieldIndex)
+	}
+	return rv, nil
+}
+
+func decodeTypeError(v *Value, rv reflect.Value) error {
+	return decodeError(v, fmt.Sprintf("Cannot decode %s into Go value of type %s", v.Kind, rv.Type()))
+}
+
+func decodeError(v *Value, message string) error {
+	return fmt.Errorf("%s: Error Decoding JSON. %s", v.Pos, message)
+}
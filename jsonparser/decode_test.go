@@ -0,0 +1,207 @@
+package jsonparser
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city,omitempty"`
+}
+
+type Timestamps struct {
+	Created int64 `json:"created"`
+	Updated *int64
+}
+
+type Person struct {
+	Name     string            `json:"name"`
+	Age      uint8             `json:"age,omitempty"`
+	Height   float64           `json:"height"`
+	Admin    bool              `json:"admin"`
+	Tags     []string          `json:"tags"`
+	Scores   [2]int            `json:"scores"`
+	Labels   map[string]string `json:"labels"`
+	Address  *Address          `json:"address"`
+	Extra    any               `json:"extra"`
+	IP       netip.Addr        `json:"ip"`
+	Ignored  string            `json:"-"`
+	internal string
+	*Timestamps
+}
+
+func TestUnmarshalStruct(t *testing.T) {
+
+	input := `{
+    "name": "Ada",
+    "age": 36,
+    "height": 1.65,
+    "admin": true,
+    "tags": ["math", "engines"],
+    "scores": [10, 20, 30],
+    "labels": {"team": "analytical"},
+    "address": {"street": "St James's Square", "city": "London"},
+    "extra": {"list": [1, "two", null]},
+    "ip": "10.0.0.1",
+    "Ignored": "not decoded",
+    "internal": "not decoded",
+    "created": 1815,
+    "UPDATED": 1852,
+    "unknown": "skipped"
+  }`
+	var person Person
+	if err := Unmarshal([]byte(input), &person); err != nil {
+		t.Fatalf("Expected no error, Got : %s", err)
+	}
+	updated := int64(1852)
+	expected := Person{
+		Name:       "Ada",
+		Age:        36,
+		Height:     1.65,
+		Admin:      true,
+		Tags:       []string{"math", "engines"},
+		Scores:     [2]int{10, 20},
+		Labels:     map[string]string{"team": "analytical"},
+		Address:    &Address{Street: "St James's Square", City: "London"},
+		Extra:      map[string]any{"list": []any{1.0, "two", nil}},
+		IP:         netip.MustParseAddr("10.0.0.1"),
+		Timestamps: &Timestamps{Created: 1815, Updated: &updated},
+	}
+	if !reflect.DeepEqual(person, expected) {
+		t.Errorf("Expected %+v, Got : %+v", expected, person)
+	}
+}
+func TestUnmarshalNull(t *testing.T) {
+
+	person := Person{Name: "Ada", Tags: []string{"math"}, Address: &Address{}}
+	if err := Unmarshal([]byte(`{"name": null, "tags": null, "address": null}`), &person); err != nil {
+		t.Fatalf("Expected no error, Got : %s", err)
+	}
+	if person.Name != "Ada" || person.Tags != nil || person.Address != nil {
+		t.Errorf("Expected name kept and tags and address cleared, Got : %+v", person)
+	}
+}
+func TestUnmarshalPrimitives(t *testing.T) {
+
+	var number int
+	var list []float32
+	var anything any
+	var pointer **string
+	cases := []struct {
+		input    string
+		target   any
+		expected any
+	}{
+		{`-42`, &number, -42},
+		{`[1.5, 2]`, &list, []float32{1.5, 2}},
+		{`{"a": [true]}`, &anything, map[string]any{"a": []any{true}}},
+		{`"deep"`, &pointer, "deep"},
+	}
+	for _, c := range cases {
+		if err := Unmarshal([]byte(c.input), c.target); err != nil {
+			t.Errorf("Expected no error for %s, Got : %s", c.input, err)
+		}
+	}
+	if number != -42 || !reflect.DeepEqual(list, []float32{1.5, 2}) || !reflect.DeepEqual(anything, cases[2].expected) || **pointer != "deep" {
+		t.Errorf("Expected decoded values, Got : %v %v %v %v", number, list, anything, pointer)
+	}
+}
+
+// Fields at the same depth with the same name cancel out unless one is tagged
+type Left struct {
+	Name string
+	ID   int
+}
+type Right struct {
+	Name string
+	Key  int `json:"ID"`
+}
+type Both struct {
+	Left
+	Right
+}
+
+func TestUnmarshalEmbeddedConflicts(t *testing.T) {
+
+	var both Both
+	if err := Unmarshal([]byte(`{"Name": "dropped", "ID": 7}`), &both); err != nil {
+		t.Fatalf("Expected no error, Got : %s", err)
+	}
+	if both.Left.Name != "" || both.Right.Name != "" || both.Right.Key != 7 || both.Left.ID != 0 {
+		t.Errorf("Expected only Right.Key to be set, Got : %+v", both)
+	}
+}
+func TestUnmarshalBytes(t *testing.T) {
+
+	var data struct {
+		Data  []byte
+		Array []byte
+	}
+	if err := Unmarshal([]byte(`{"Data": "aGk=", "Array": [1, 2]}`), &data); err != nil || string(data.Data) != "hi" || len(data.Array) != 2 {
+		t.Errorf("Expected the base64 string to be decoded, Got : %+v %v", data, err)
+	}
+	expected := "1:10: Error Decoding JSON. Invalid base64 for []uint8: illegal base64 data at input byte 0"
+	if err := Unmarshal([]byte(`{"Data": "aGk"}`), &data); err == nil || err.Error() != expected {
+		t.Errorf("Expected %q, Got : %v", expected, err)
+	}
+}
+func TestUnmarshalMapKeys(t *testing.T) {
+
+	ids := map[int]string{1: "a", -20: "b"}
+	data, err := Marshal(ids)
+	if err != nil {
+		t.Fatalf("Expected no error, Got : %s", err)
+	}
+	var decodedIDs map[int]string
+	if err := Unmarshal(data, &decodedIDs); err != nil || !reflect.DeepEqual(decodedIDs, ids) {
+		t.Errorf("Expected %v, Got : %v %v", ids, decodedIDs, err)
+	}
+	var hosts map[netip.Addr]uint
+	if err := Unmarshal([]byte(`{"127.0.0.1": 8080}`), &hosts); err != nil || hosts[netip.MustParseAddr("127.0.0.1")] != 8080 {
+		t.Errorf("Expected 127.0.0.1 to be decoded as a netip.Addr key, Got : %v %v", hosts, err)
+	}
+
+	cases := []struct {
+		input    string
+		target   any
+		expected string
+	}{
+		{`{"x": 1}`, &map[int]int{}, `1:1: Error Decoding JSON. Cannot decode key "x" into Go value of type int`},
+		{`{"300": 1}`, &map[uint8]int{}, `1:1: Error Decoding JSON. Cannot decode key "300" into Go value of type uint8`},
+		{`{"-1": 1}`, &map[uint]int{}, `1:1: Error Decoding JSON. Cannot decode key "-1" into Go value of type uint`},
+		{`{"bad": 1}`, &map[netip.Addr]int{}, `1:1: Error Decoding JSON. ParseAddr("bad"): unable to parse IP`},
+		{`{"a": 1}`, &map[float64]int{}, "1:1: Error Decoding JSON. Cannot decode object into Go value of type map[float64]int"},
+	}
+	for _, c := range cases {
+		if err := Unmarshal([]byte(c.input), c.target); err == nil || err.Error() != c.expected {
+			t.Errorf("Expected %q for %s, Got : %v", c.expected, c.input, err)
+		}
+	}
+}
+func TestUnmarshalErrors(t *testing.T) {
+
+	cases := map[string]string{
+		`{"name": 5}`:                    "1:10: Error Decoding JSON. Cannot decode number into Go value of type string",
+		"{\n  \"age\": 300\n}":           "2:10: Error Decoding JSON. Number 300 overflows uint8",
+		`{"age": -1}`:                    "1:9: Error Decoding JSON. Cannot decode number into Go value of type uint8",
+		`{"scores": [1.5]}`:              "1:13: Error Decoding JSON. Cannot decode number into Go value of type int",
+		`{"labels": []}`:                 "1:12: Error Decoding JSON. Cannot decode array into Go value of type map[string]string",
+		`{"ip": "not an ip"}`:            "1:8: Error Decoding JSON. ParseAddr(\"not an ip\"): unable to parse IP",
+		`{"address": {"street": "x",}}`:  "1:28: Error Parsing JSON. Expected string but got }",
+		`{"address": {"street": ["x"]}}`: "1:24: Error Decoding JSON. Cannot decode array into Go value of type string",
+	}
+	for input, expected := range cases {
+		var person Person
+		err := Unmarshal([]byte(input), &person)
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %s, Got : %v", expected, input, err)
+		}
+	}
+	var person Person
+	if err := Unmarshal([]byte(`{}`), person); err == nil || !strings.Contains(err.Error(), "non-nil pointer") {
+		t.Errorf("Expected error for non-pointer target, Got : %v", err)
+	}
+}
@@ -0,0 +1,476 @@
+package jsonparser
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Returns v as compact JSON. v can be a *Value from Parse or any Go value that encoding/json could encode
+func Marshal(v any) ([]byte, error) {
+	return EncodeOptions{}.Marshal(v)
+}
+
+// Returns v as JSON with each element and member on its own line, indented by indent
+func MarshalIndent(v any, indent string) ([]byte, error) {
+	return EncodeOptions{Indent: indent}.Marshal(v)
+}
+
+// Returns v as JSON. The encoding follows the same rules as decoding:
+//
+//   - structs become objects, using the field name or its `json:"name"` tag, and omitempty fields are
+//     left out when they hold false, 0, "", nil or an empty array, slice or map
+//   - maps become objects with their keys sorted, slices and arrays become arrays
+//   - nil pointers, interfaces, maps and slices become null
+//   - values that implement encoding.TextMarshaler become strings
+//   - a []byte becomes a base64 string
+//   - a *Value becomes the JSON it was parsed from, with members kept in order and numbers as they were written
+//   - a Number is written as it is, as a number rather than a string
+func (o EncodeOptions) Marshal(v any) ([]byte, error) {
+	e := &encoder{options: o}
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// Writes v to w as JSON followed by a newline
+func (o EncodeOptions) Encode(w io.Writer, v any) error {
+	data, err := o.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+type encoder struct {
+	buf     bytes.Buffer
+	options EncodeOptions
+	depth   int
+	// the pointers, maps, slices and *Values being encoded, to catch values that contain themselves
+	visiting map[visit]bool
+}
+
+// Identifies a pointer, map or slice. Slices of different lengths over the same array are
+// different values
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// Marks rv, a non-nil pointer, map or slice, as being encoded until leave is called with it.
+// Fails if it is already being encoded, since encoding it again would never end
+func (e *encoder) enter(rv reflect.Value) error {
+	key := visit{ptr: rv.Pointer(), typ: rv.Type()}
+	if rv.Kind() == reflect.Slice {
+		key.len = rv.Len()
+	}
+	if e.visiting[key] {
+		return encodeError(fmt.Sprintf("Cannot encode Go value of type %s that contains itself", rv.Type()))
+	}
+	if e.visiting == nil {
+		e.visiting = map[visit]bool{}
+	}
+	e.visiting[key] = true
+	return nil
+}
+func (e *encoder) leave(rv reflect.Value) {
+	key := visit{ptr: rv.Pointer(), typ: rv.Type()}
+	if rv.Kind() == reflect.Slice {
+		key.len = rv.Len()
+	}
+	delete(e.visiting, key)
+}
+
+var (
+	valueType         = reflect.TypeFor[*Value]()
+	numberType        = reflect.TypeFor[Number]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func (e *encoder) encode(rv reflect.Value) error {
+	if !rv.IsValid() {
+		e.buf.WriteString(NULL.String())
+		return nil
+	}
+	if rv.Type() == valueType {
+		if rv.IsNil() {
+			e.buf.WriteString(NULL.String())
+			return nil
+		}
+		if err := e.enter(rv); err != nil {
+			return err
+		}
+		defer e.leave(rv)
+		return e.encodeValue(rv.Interface().(*Value))
+	}
+	if rv.Type() == numberType {
+		return e.writeNumber(rv.Interface().(Number))
+	}
+	if rv.Type().Implements(textMarshalerType) {
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			e.buf.WriteString(NULL.String())
+			return nil
+		}
+		text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return encodeError(err.Error())
+		}
+		e.writeString(string(text))
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			e.buf.WriteString(NULL.String())
+			return nil
+		}
+		if rv.Kind() == reflect.Pointer {
+			if err := e.enter(rv); err != nil {
+				return err
+			}
+			defer e.leave(rv)
+		}
+		return e.encode(rv.Elem())
+	case reflect.Bool:
+		e.buf.WriteString(strconv.FormatBool(rv.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.buf.WriteString(strconv.FormatInt(rv.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.buf.WriteString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return e.writeFloat(rv.Float(), rv.Type().Bits())
+	case reflect.String:
+		e.writeString(rv.String())
+	case reflect.Slice:
+		if rv.IsNil() {
+			e.buf.WriteString(NULL.String())
+			return nil
+		}
+		if isByteSlice(rv.Type()) {
+			e.writeString(base64.StdEncoding.EncodeToString(rv.Bytes()))
+			return nil
+		}
+		if err := e.enter(rv); err != nil {
+			return err
+		}
+		defer e.leave(rv)
+		return e.encodeArray(rv)
+	case reflect.Array:
+		return e.encodeArray(rv)
+	case reflect.Map:
+		if rv.IsNil() {
+			e.buf.WriteString(NULL.String())
+			return nil
+		}
+		if err := e.enter(rv); err != nil {
+			return err
+		}
+		defer e.leave(rv)
+		return e.encodeMap(rv)
+	case reflect.Struct:
+		return e.encodeStruct(rv)
+	default:
+		return encodeError(fmt.Sprintf("Cannot encode Go value of type %s", rv.Type()))
+	}
+	return nil
+}
+
+func (e *encoder) encodeArray(rv reflect.Value) error {
+	e.open('[')
+	for i := range rv.Len() {
+		e.separate(i)
+		if err := e.encode(rv.Index(i)); err != nil {
+			return err
+		}
+	}
+	e.close(']', rv.Len())
+	return nil
+}
+
+func (e *encoder) encodeMap(rv reflect.Value) error {
+	type member struct {
+		key   string
+		value reflect.Value
+	}
+	members := []member{}
+	for iter := rv.MapRange(); iter.Next(); {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		members = append(members, member{key, iter.Value()})
+	}
+	slices.SortFunc(members, func(a, b member) int {
+		return strings.Compare(a.key, b.key)
+	})
+	e.open('{')
+	for i, member := range members {
+		e.separate(i)
+		e.writeKey(member.key)
+		if err := e.encode(member.value); err != nil {
+			return err
+		}
+	}
+	e.close('}', len(members))
+	return nil
+}
+
+// Reports whether t is a slice of bytes, which is written as a base64 string like encoding/json
+// does unless its elements are TextMarshalers of their own
+func isByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 &&
+		!reflect.PointerTo(t.Elem()).Implements(textMarshalerType)
+}
+
+// Converts a map key to the string used for its member name
+func mapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if key.Type().Implements(textMarshalerType) {
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", encodeError(err.Error())
+		}
+		return string(text), nil
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", encodeError(fmt.Sprintf("Cannot encode map key of type %s", key.Type()))
+}
+
+func (e *encoder) encodeStruct(rv reflect.Value) error {
+	e.open('{')
+	count := 0
+	for _, field := range structFields(rv.Type()) {
+		fieldValue, ok := fieldByIndexNoAlloc(rv, field.index)
+		if !ok || (field.omitEmpty && isEmpty(fieldValue)) {
+			continue
+		}
+		e.separate(count)
+		e.writeKey(field.name)
+		if err := e.encode(fieldValue); err != nil {
+			return err
+		}
+		count++
+	}
+	e.close('}', count)
+	return nil
+}
+
+// Returns the field at index, or false if it sits behind a nil embedded struct pointer
+func fieldByIndexNoAlloc(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(fieldIndex)
+	}
+	return rv, true
+}
+
+// Reports whether omitempty leaves the value out
+func isEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return rv.IsZero()
+	}
+	return false
+}
+
+// Writes out a value tree built by Parse
+func (e *encoder) encodeValue(v *Value) error {
+	switch v.Kind {
+	case NullValue:
+		e.buf.WriteString(NULL.String())
+	case BoolValue:
+		e.buf.WriteString(strconv.FormatBool(v.Bool))
+	case NumberValue:
+		if text, ok := v.Number.jsonText(); ok {
+			e.buf.WriteString(text)
+			return nil
+		}
+		return e.writeFloat(v.Num, 64)
+	case StringValue:
+		e.writeString(v.Str)
+	case ArrayValue:
+		e.open('[')
+		for i, element := range v.Elements {
+			e.separate(i)
+			if err := e.encodeChild(element); err != nil {
+				return err
+			}
+		}
+		e.close(']', len(v.Elements))
+	case ObjectValue:
+		members := v.Members
+		if e.options.SortKeys {
+			members = slices.Clone(members)
+			slices.SortStableFunc(members, func(a, b Member) int {
+				return strings.Compare(a.Key, b.Key)
+			})
+		}
+		e.open('{')
+		for i, member := range members {
+			e.separate(i)
+			e.writeKey(member.Key)
+			if err := e.encodeChild(member.Value); err != nil {
+				return err
+			}
+		}
+		e.close('}', len(v.Members))
+	}
+	return nil
+}
+
+// Writes out an element or member value of a value tree, which could be one of the objects or
+// arrays it is inside of if the tree was put together by hand
+func (e *encoder) encodeChild(v *Value) error {
+	rv := reflect.ValueOf(v)
+	if err := e.enter(rv); err != nil {
+		return err
+	}
+	defer e.leave(rv)
+	return e.encodeValue(v)
+}
+
+func (e *encoder) open(bracket byte) {
+	e.buf.WriteByte(bracket)
+	e.depth++
+}
+
+// Writes the comma before every element or member but the first, and the line break before each one when indenting
+func (e *encoder) separate(i int) {
+	if i > 0 {
+		e.buf.WriteByte(',')
+	}
+	e.newline()
+}
+
+// count is how many elements or members were written so empty ones stay on one line
+func (e *encoder) close(bracket byte, count int) {
+	e.depth--
+	if count > 0 {
+		e.newline()
+	}
+	e.buf.WriteByte(bracket)
+}
+
+func (e *encoder) newline() {
+	if e.options.Indent == "" {
+		return
+	}
+	e.buf.WriteByte('\n')
+	for range e.depth {
+		e.buf.WriteString(e.options.Indent)
+	}
+}
+
+func (e *encoder) writeKey(key string) {
+	e.writeString(key)
+	e.buf.WriteByte(':')
+	if e.options.Indent != "" {
+		e.buf.WriteByte(' ')
+	}
+}
+
+// Writes floats the same way encoding/json does, switching to exponents for very large and very small numbers
+func (e *encoder) writeFloat(f float64, bits int) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return encodeError(fmt.Sprintf("Cannot encode %v as a JSON number", f))
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21)) {
+		format = 'e'
+	}
+	text := strconv.FormatFloat(f, format, -1, bits)
+	if format == 'e' {
+		// shorten e-09 to e-9
+		if n := len(text); n >= 4 && text[n-4] == 'e' && text[n-3] == '-' && text[n-2] == '0' {
+			text = text[:n-2] + text[n-1:]
+		}
+	}
+	e.buf.WriteString(text)
+	return nil
+}
+
+// Writes a Number as it is, or 0 when it's empty like encoding/json does
+func (e *encoder) writeNumber(n Number) error {
+	if n == "" {
+		e.buf.WriteByte('0')
+		return nil
+	}
+	text, ok := n.jsonText()
+	if !ok {
+		return encodeError(fmt.Sprintf("Cannot encode %q as a JSON number", string(n)))
+	}
+	e.buf.WriteString(text)
+	return nil
+}
+
+const hex = "0123456789abcdef"
+
+// Writes s as a quoted JSON string. Invalid UTF-8 is replaced with U+FFFD and
+// U+2028 and U+2029 are always escaped since JavaScript treats them as line breaks
+func (e *encoder) writeString(s string) {
+	e.buf.WriteByte('"')
+	for i := 0; i < len(s); {
+		char, width := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case char == '"' || char == '\\':
+			e.buf.WriteByte('\\')
+			e.buf.WriteByte(byte(char))
+		case char == '\n':
+			e.buf.WriteString(`\n`)
+		case char == '\r':
+			e.buf.WriteString(`\r`)
+		case char == '\t':
+			e.buf.WriteString(`\t`)
+		case char == '\b':
+			e.buf.WriteString(`\b`)
+		case char == '\f':
+			e.buf.WriteString(`\f`)
+		case char < 0x20, char == '\u2028', char == '\u2029',
+			e.options.EscapeHTML && (char == '<' || char == '>' || char == '&'):
+			e.buf.WriteString(`\u`)
+			e.buf.WriteByte(hex[char>>12&0xF])
+			e.buf.WriteByte(hex[char>>8&0xF])
+			e.buf.WriteByte(hex[char>>4&0xF])
+			e.buf.WriteByte(hex[char&0xF])
+		case char == utf8.RuneError && width == 1:
+			e.buf.WriteString(`\ufffd`)
+		default:
+			e.buf.WriteString(s[i : i+width])
+		}
+		i += width
+	}
+	e.buf.WriteByte('"')
+}
+
+func encodeError(message string) error {
+	return fmt.Errorf("Error Encoding JSON. %s", message)
+}
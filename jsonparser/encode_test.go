@@ -0,0 +1,154 @@
+package jsonparser
+
+import (
+	"bytes"
+	"math"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestMarshalStruct(t *testing.T) {
+
+	updated := int64(1852)
+	person := Person{
+		Name:       "Ada",
+		Height:     1.65,
+		Tags:       []string{"math"},
+		Labels:     map[string]string{"team": "analytical", "era": "victorian"},
+		Address:    &Address{Street: "St James's Square"},
+		IP:         netip.MustParseAddr("10.0.0.1"),
+		Ignored:    "hidden",
+		internal:   "hidden",
+		Timestamps: &Timestamps{Created: 1815, Updated: &updated},
+	}
+	data, err := Marshal(person)
+	if err != nil {
+		t.Fatalf("Expected no error, Got : %s", err)
+	}
+	expected := `{"name":"Ada","height":1.65,"admin":false,"tags":["math"],"scores":[0,0],` +
+		`"labels":{"era":"victorian","team":"analytical"},"address":{"street":"St James's Square"},` +
+		`"extra":null,"ip":"10.0.0.1","created":1815,"Updated":1852}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, Got : %s", expected, data)
+	}
+	var decoded Person
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected output to decode, Got : %s", err)
+	}
+	if decoded.Name != person.Name || decoded.Labels["era"] != "victorian" || *decoded.Updated != updated {
+		t.Errorf("Expected round trip to keep values, Got : %+v", decoded)
+	}
+}
+func TestMarshalIndent(t *testing.T) {
+
+	value := map[string]any{"list": []any{1, "two", []int{}}, "empty": map[string]int{}, "none": nil}
+	data, err := MarshalIndent(value, "  ")
+	if err != nil {
+		t.Fatalf("Expected no error, Got : %s", err)
+	}
+	expected := `{
+  "empty": {},
+  "list": [
+    1,
+    "two",
+    []
+  ],
+  "none": null
+}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, Got : %s", expected, data)
+	}
+}
+func TestMarshalValueTree(t *testing.T) {
+
+	input := `{"z": [1, 2.5, -3e-7, 1e21], "a": {"nested": [true, false, null]}, "s": "é\n"}`
+	value, err := ParseBuffer(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("Expected valid but got invalid: %s", err)
+	}
+	data, err := Marshal(value)
+	if err != nil {
+		t.Fatalf("Expected no error, Got : %s", err)
+	}
+	expected := `{"z":[1,2.5,-3e-7,1e21],"a":{"nested":[true,false,null]},"s":"é\n"}`
+	if string(data) != expected {
+		t.Errorf("Expected members in input order %s, Got : %s", expected, data)
+	}
+}
+func TestMarshalSortKeys(t *testing.T) {
+
+	value, err := ParseBuffer(bytes.NewBufferString(`{"b": 1, "a": {"d": 2, "c": 3}, "b": 4}`))
+	if err != nil {
+		t.Fatalf("Expected valid but got invalid: %s", err)
+	}
+	data, _ := EncodeOptions{SortKeys: true}.Marshal(value)
+	expected := `{"a":{"c":3,"d":2},"b":1,"b":4}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, Got : %s", expected, data)
+	}
+	if data, _ := Marshal(value); string(data) != `{"b":1,"a":{"d":2,"c":3},"b":4}` {
+		t.Errorf("Expected members to stay in input order without SortKeys, Got : %s", data)
+	}
+}
+func TestMarshalStringEscapes(t *testing.T) {
+
+	input := "quote\" backslash\\ tab\t nul\x00 html<>& line\u2028 bad\xff"
+	data, _ := Marshal(input)
+	expected := `"quote\" backslash\\ tab\t nul\u0000 html<>& line\u2028 bad\ufffd"`
+	if string(data) != expected {
+		t.Errorf("Expected %s, Got : %s", expected, data)
+	}
+	data, _ = EncodeOptions{EscapeHTML: true}.Marshal("<a href='x'>&</a>")
+	expected = `"\u003ca href='x'\u003e\u0026\u003c/a\u003e"`
+	if string(data) != expected {
+		t.Errorf("Expected %s, Got : %s", expected, data)
+	}
+}
+func TestMarshalBytes(t *testing.T) {
+
+	data, err := Marshal(struct {
+		Data  []byte
+		Empty []byte
+		Fixed [2]byte
+	}{Data: []byte("hi")})
+	if expected := `{"Data":"aGk=","Empty":null,"Fixed":[0,0]}`; err != nil || string(data) != expected {
+		t.Errorf("Expected %s, Got : %s %v", expected, data, err)
+	}
+}
+func TestMarshalErrors(t *testing.T) {
+
+	for _, value := range []any{math.NaN(), math.Inf(1), make(chan int), map[[2]int]int{{1, 2}: 3}} {
+		if _, err := Marshal(value); err == nil || !strings.HasPrefix(err.Error(), "Error Encoding JSON.") {
+			t.Errorf("Expected encoding error for %T, Got : %v", value, err)
+		}
+	}
+}
+
+type node struct {
+	Next *node
+}
+
+func TestMarshalCycles(t *testing.T) {
+
+	n := &node{}
+	n.Next = n
+	m := map[string]any{}
+	m["self"] = m
+	s := []any{nil}
+	s[0] = s
+	array := &Value{Kind: ArrayValue}
+	array.Elements = []*Value{array}
+	for _, value := range []any{n, m, s, array} {
+		if _, err := Marshal(value); err == nil || !strings.Contains(err.Error(), "contains itself") {
+			t.Errorf("Expected an error for a %T that contains itself, Got : %v", value, err)
+		}
+	}
+
+	// the same value twice side by side isn't a cycle
+	shared := &node{}
+	data, err := Marshal([]*node{shared, shared})
+	if expected := `[{"Next":null},{"Next":null}]`; err != nil || string(data) != expected {
+		t.Errorf("Expected %s, Got : %s %v", expected, data, err)
+	}
+}
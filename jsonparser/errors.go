@@ -0,0 +1,156 @@
+package jsonparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Identifies what is wrong with the input for a SyntaxError. Codes keep their values
+// between releases, new ones are only added at the end
+type ErrorCode int
+
+const (
+	// A token the grammar doesn't allow at this point
+	UnexpectedToken ErrorCode = iota
+	// The input ended in the middle of a value
+	UnexpectedEOF
+	// A comma right before the closing bracket of an array or object
+	TrailingComma
+	// Something other than whitespace after the top level value
+	TrailingData
+	// An object key that isn't followed by a colon
+	MissingColon
+	// Two array elements or object members without a comma between them
+	MissingComma
+	// A top level value that isn't an object or array when Options.RFC4627 is set
+	ScalarTopLevel
+	// A number that doesn't follow the JSON grammar
+	InvalidNumber
+	// A string without a closing quote
+	UnterminatedString
+	// A backslash in a string that isn't followed by a valid escape
+	InvalidEscape
+	// A \u escape for half of a UTF-16 surrogate pair without the other half
+	LoneSurrogate
+	// A character below U+0020 in a string that isn't escaped
+	ControlCharacter
+	// Bytes in a string that aren't valid UTF-8
+	InvalidUTF8
+	// A word that isn't true, false or null
+	InvalidLiteral
+	// A character that can't start a token
+	UnexpectedCharacter
+	// A /* comment without the closing */ in JSONC mode
+	UnterminatedComment
+	// An object key that already appeared in the same object, when Options.DuplicateKeys rejects
+	// or warns about them
+	DuplicateKey
+	// Objects and arrays nested deeper than Options.MaxDepth
+	TooDeep
+	// More input than Options.MaxInputBytes
+	InputTooLarge
+	// More tokens than Options.MaxTokens
+	TooManyTokens
+	// A string or JSON5 identifier longer than Options.MaxStringLength
+	StringTooLong
+	// A number longer than Options.MaxNumberLength
+	NumberTooLong
+	// An object with more members than Options.MaxMembers
+	TooManyMembers
+)
+
+var errorCodeNames = [...]string{
+	UnexpectedToken:     "UnexpectedToken",
+	UnexpectedEOF:       "UnexpectedEOF",
+	TrailingComma:       "TrailingComma",
+	TrailingData:        "TrailingData",
+	MissingColon:        "MissingColon",
+	MissingComma:        "MissingComma",
+	ScalarTopLevel:      "ScalarTopLevel",
+	InvalidNumber:       "InvalidNumber",
+	UnterminatedString:  "UnterminatedString",
+	InvalidEscape:       "InvalidEscape",
+	LoneSurrogate:       "LoneSurrogate",
+	ControlCharacter:    "ControlCharacter",
+	InvalidUTF8:         "InvalidUTF8",
+	InvalidLiteral:      "InvalidLiteral",
+	UnexpectedCharacter: "UnexpectedCharacter",
+	UnterminatedComment: "UnterminatedComment",
+	DuplicateKey:        "DuplicateKey",
+	TooDeep:             "TooDeep",
+	InputTooLarge:       "InputTooLarge",
+	TooManyTokens:       "TooManyTokens",
+	StringTooLong:       "StringTooLong",
+	NumberTooLong:       "NumberTooLong",
+	TooManyMembers:      "TooManyMembers",
+}
+
+func (c ErrorCode) String() string {
+	if c < 0 || int(c) >= len(errorCodeNames) {
+		return fmt.Sprintf("ErrorCode(%d)", int(c))
+	}
+	return errorCodeNames[c]
+}
+
+// Reports whether the code is for input that goes past one of the limits in Options, which
+// stops parsing whatever Options.MaxErrors says
+func (c ErrorCode) limit() bool {
+	switch c {
+	case TooDeep, InputTooLarge, TooManyTokens, StringTooLong, NumberTooLong, TooManyMembers:
+		return true
+	}
+	return false
+}
+
+// An error in the JSON input, found while lexing or parsing it.
+// Use errors.As to get at the details of an error returned by the parser
+type SyntaxError struct {
+	Code ErrorCode
+	// Set when the error was found by the lexer, like an unterminated string, rather than the parser
+	Lexing bool
+	// What the parser was looking for, like ":" or "value". Empty when there is nothing more specific
+	// to say than the message
+	Expected string
+	// The text found instead, or EOF at the end of the input
+	Actual string
+	// The offending text spans from Pos up to but not including End
+	Pos Position
+	End Position
+	// Describes the error without the position
+	Msg string
+	// Where the key first appeared, for a DuplicateKey error
+	Original Position
+	// Set for problems that don't make the input invalid, which are added to Options.Warnings
+	// rather than returned
+	Warning bool
+}
+
+func (e *SyntaxError) Error() string {
+	stage, severity := "Parsing", "Error"
+	if e.Lexing {
+		stage = "Lexing"
+	}
+	if e.Warning {
+		severity = "Warning"
+	}
+	return fmt.Sprintf("%s: %s %s JSON. %s", e.Pos, severity, stage, e.Msg)
+}
+
+// Every syntax error found in the input when Options.MaxErrors asks for more than one, in the
+// order they appear. errors.As finds the first of them
+type ErrorList []*SyntaxError
+
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, err := range l {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+func (l ErrorList) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, err := range l {
+		errs[i] = err
+	}
+	return errs
+}
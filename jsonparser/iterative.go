@@ -0,0 +1,146 @@
+package jsonparser
+
+// An object or array that parseIterative is part way through
+type frame struct {
+	container *Value
+	// the key of the member whose value is being parsed, for objects
+	key Token
+	// where each key was first seen, when duplicates need to be found
+	seen map[string]firstKey
+	// number of members of an object so far
+	members int
+}
+
+// Parses the same grammar as parseValues, keeping the objects and arrays that are open on a
+// stack instead of recursing into them. Stops at the first error. Leaves the token after the
+// value up next
+func (p *parser) parseIterative() (*Value, error) {
+	var stack []*frame
+	for {
+		// a value starts at the current token
+		var value *Value
+		if kind := p.token.Kind; kind == LEFTCURLYBRACE || kind == LEFTSQUAREBRACE {
+			if err := p.enter(); err != nil {
+				return nil, err
+			}
+			f := &frame{container: &Value{Kind: ArrayValue, Pos: p.token.Pos, Elements: []*Value{}}}
+			if kind == LEFTCURLYBRACE {
+				f.container = &Value{Kind: ObjectValue, Pos: p.token.Pos, Members: []Member{}}
+				if p.options.DuplicateKeys != AllowDuplicateKeys {
+					f.seen = map[string]firstKey{}
+				}
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if p.token.Kind != closingOf(f.container) {
+				stack = append(stack, f)
+				if kind == LEFTCURLYBRACE {
+					if err := p.parseKey(f); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			}
+			p.depth--
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			value = f.container
+		} else {
+			var err error
+			if value, err = p.parseScalar(); err != nil {
+				return nil, err
+			}
+		}
+
+		// the value is complete, so add it to the enclosing object or array and close
+		// every one that ends after it
+		for {
+			if len(stack) == 0 {
+				return value, nil
+			}
+			f := stack[len(stack)-1]
+			if f.container.Kind == ObjectValue {
+				if err := p.addMember(f.container, f.seen, f.key, value); err != nil {
+					return nil, err
+				}
+			} else if p.build {
+				f.container.Elements = append(f.container.Elements, value)
+			}
+			more, err := p.parseIterativeEnding(f)
+			if err != nil {
+				return nil, err
+			}
+			if more {
+				break
+			}
+			stack = stack[:len(stack)-1]
+			p.depth--
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			value = f.container
+		}
+	}
+}
+
+// Parses the token after an element of an array or a member of an object like parseValueEnding.
+// more is set when another element or member follows, which is up next. Otherwise the closing
+// bracket is
+func (p *parser) parseIterativeEnding(f *frame) (more bool, err error) {
+	closing := closingOf(f.container)
+	switch p.token.Kind {
+	case closing:
+		return false, nil
+	case COMMA:
+		if err := p.next(); err != nil {
+			return false, err
+		}
+		if p.token.Kind == closing {
+			if p.options.JSON5 {
+				return false, nil
+			}
+			expected := "value"
+			if closing == RIGHTCURLYBRACE {
+				expected = p.keyExpected()
+			}
+			return false, parserError(p.token, TrailingComma, expected)
+		}
+		if closing == RIGHTCURLYBRACE {
+			return true, p.parseKey(f)
+		}
+		return true, nil
+	}
+	code := UnexpectedToken
+	if startsValue(p.token.Kind) || (closing == RIGHTCURLYBRACE && p.isKey(p.token)) {
+		code = MissingComma
+	}
+	return false, parserError(p.token, code, closing.String())
+}
+
+// Reads the key of an object member and the colon after it, leaving the value up next
+func (p *parser) parseKey(f *frame) error {
+	if !p.isKey(p.token) {
+		return parserError(p.token, UnexpectedToken, p.keyExpected())
+	}
+	if err := p.countMember(&f.members); err != nil {
+		return err
+	}
+	f.key = p.token
+	if err := p.next(); err != nil {
+		return err
+	}
+	if p.token.Kind != COLON {
+		return parserError(p.token, MissingColon, COLON.String())
+	}
+	return p.next()
+}
+
+// Returns the kind of token that closes an object or array
+func closingOf(container *Value) TokenKind {
+	if container.Kind == ObjectValue {
+		return RIGHTCURLYBRACE
+	}
+	return RIGHTSQUAREBRACE
+}
@@ -0,0 +1,90 @@
+package jsonparser
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Checks that the iterative parser gives the same tree or error as the recursive one
+func TestIterativeMatchesRecursive(t *testing.T) {
+
+	inputs := []string{
+		`{"a": [1, 2, {"b": null}], "c": {"d": [[], {}]}, "e": "f"}`, `[]`, `{}`, `"top"`, `[[[[1]]]]`,
+		`[1, 2,]`, `{"key": 1,}`, `[1 2]`, `{"a": 1 "b": 2}`, `[1 :]`, `{1: 2}`, `{"key" 1}`,
+		`[1, 2`, `{"key"`, ``, `[] []`, `[01]`, `{"a": [}`, `[{"a": 1}, {"b": 2]`, `{"a": 1, "a": 2}`,
+	}
+	for _, json := range validFiles {
+		data, err := os.ReadFile(json.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, string(data))
+	}
+	options := []Options{{}, {JSON5: true}, {RFC4627: true}, {DuplicateKeys: RejectDuplicateKeys}, {DuplicateKeys: KeepLastDuplicateKey}}
+	for _, input := range inputs {
+		for _, o := range options {
+			expected, expectedErr := o.ParseReader(strings.NewReader(input))
+			o.Iterative = true
+			value, err := o.ParseReader(strings.NewReader(input))
+			if !reflect.DeepEqual(value, expected) || !reflect.DeepEqual(err, expectedErr) {
+				t.Errorf("%+v %q: Expected %v %v, Got : %v %v", o, input, expected, expectedErr, value, err)
+			}
+		}
+	}
+}
+
+// Produces [[[...]]] nested depth deep without holding it in memory
+type nestedArrays struct {
+	depth, pos int
+}
+
+func (n *nestedArrays) Read(p []byte) (int, error) {
+	if n.pos == 2*n.depth {
+		return 0, io.EOF
+	}
+	for i := range p {
+		if n.pos == 2*n.depth {
+			return i, nil
+		}
+		p[i] = '['
+		if n.pos >= n.depth {
+			p[i] = ']'
+		}
+		n.pos++
+	}
+	return len(p), nil
+}
+func TestMaxDepth(t *testing.T) {
+
+	for _, iterative := range []bool{false, true} {
+		options := Options{Iterative: iterative}
+		if err := options.Validate(&nestedArrays{depth: DefaultMaxDepth}); err != nil {
+			t.Errorf("Expected valid at the default depth, Got : %s", err)
+		}
+		err := options.Validate(&nestedArrays{depth: DefaultMaxDepth + 1})
+		expected := "1:1001: Error Parsing JSON. Objects and arrays are nested more than 1000 deep"
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Code != TooDeep || err.Error() != expected {
+			t.Errorf("Expected %q, Got : %v", expected, err)
+		}
+		options.MaxDepth = 2
+		if err := options.Validate(strings.NewReader(`{"a": [1, {"b": 2}]}`)); err == nil || !strings.HasPrefix(err.Error(), "1:11: ") {
+			t.Errorf("Expected an error at 1:11 with a depth of 2, Got : %v", err)
+		}
+	}
+	err := Options{MaxDepth: 3, MaxErrors: 5}.Validate(strings.NewReader(`[1 2, [[[]]]]`))
+	var list ErrorList
+	if !errors.As(err, &list) || len(list) != 2 || list[1].Code != TooDeep {
+		t.Errorf("Expected parsing to stop at the depth limit, Got : %v", err)
+	}
+}
+func TestIterativeDeepNesting(t *testing.T) {
+
+	if err := (Options{Iterative: true, MaxDepth: -1}).Validate(&nestedArrays{depth: 1000000}); err != nil {
+		t.Errorf("Expected valid, Got : %s", err)
+	}
+}
@@ -0,0 +1,82 @@
+package jsonparser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Writes the JSON5 tokens it hands out to w as strict JSON
+type json5Tokens struct {
+	tokens TokenReader
+	w      *bufio.Writer
+	// whether each object or array that is open is an object
+	objects []bool
+	// set when the next token is an object key, which is quoted whatever its kind
+	key bool
+	// a comma is only written once the token after it shows it isn't a trailing one
+	comma bool
+}
+
+func (t *json5Tokens) Next() (Token, error) {
+	token, err := t.tokens.Next()
+	if err != nil || token.Kind == COMMENT || token.Kind == EOF {
+		return token, err
+	}
+	closing := token.Kind == RIGHTCURLYBRACE || token.Kind == RIGHTSQUAREBRACE
+	if t.comma && !closing {
+		t.w.WriteByte(',')
+	}
+	t.comma = false
+	key := t.key && !closing
+	t.key = false
+	text := token.Text
+	switch token.Kind {
+	case LEFTCURLYBRACE, LEFTSQUAREBRACE:
+		t.objects = append(t.objects, token.Kind == LEFTCURLYBRACE)
+		t.key = token.Kind == LEFTCURLYBRACE
+	case RIGHTCURLYBRACE, RIGHTSQUAREBRACE:
+		if len(t.objects) > 0 {
+			t.objects = t.objects[:len(t.objects)-1]
+		}
+	case COMMA:
+		t.comma = true
+		t.key = len(t.objects) > 0 && t.objects[len(t.objects)-1]
+		return token, nil
+	case NUMBER:
+		if key {
+			break
+		}
+		var ok bool
+		if text, ok = Number(token.Text).jsonText(); !ok {
+			return token, fmt.Errorf("%s: Error Converting JSON5. %s has no equivalent in JSON", token.Pos, token.Text)
+		}
+	}
+	if key || token.Kind == STRING || token.Kind == IDENTIFIER {
+		quoted, _ := Marshal(token.Value)
+		text = string(quoted)
+	}
+	if _, err := t.w.WriteString(text); err != nil {
+		return token, err
+	}
+	return token, nil
+}
+
+// Converts the JSON5 in r to compact JSON on w
+func JSON5ToJSON(w io.Writer, r io.Reader) error {
+	return Options{}.JSON5ToJSON(w, r)
+}
+
+// Converts the JSON5 in r to compact JSON on w. JSON5 is always accepted whatever o says.
+// Comments are dropped, keys and strings are written with double quotes and numbers are
+// written the way JSON spells them. Infinity and NaN can't be converted and are errors.
+// Like Minify, the input is converted as it is read and w may have been partly written to
+// by the time an error is found
+func (o Options) JSON5ToJSON(w io.Writer, r io.Reader) error {
+	o.JSON5, o.KeepComments = true, false
+	buffered := bufio.NewWriter(w)
+	if _, err := o.parse(&json5Tokens{tokens: o.NewLexer(r), w: buffered}, false); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
@@ -0,0 +1,48 @@
+package jsonparser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJSON5ToJSON(t *testing.T) {
+
+	inputs := map[string]string{
+		"{unquoted: 'single', \"double\": \"x\",}":         `{"unquoted":"single","double":"x"}`,
+		"{null: true, Infinity: 1, $\\u0061: 2}":           `{"null":true,"Infinity":1,"$a":2}`,
+		"[0xFF, -0x10, +1, .5, -.5, 5., 5.e3, 1.5e2]":      `[255,-16,1,0.5,-0.5,5,5e3,1.5e2]`,
+		"// comment\n[ /* inline */ 'a\\\nb', \"it's\" ,]": `["ab","it's"]`,
+		"'\\x41\\v\\0\"'":             `"A\u000b\u0000\""`,
+		"{a: [{}, [], {b: [1,],},],}": `{"a":[{},[],{"b":[1]}]}`,
+	}
+	for input, expected := range inputs {
+		var out bytes.Buffer
+		if err := JSON5ToJSON(&out, strings.NewReader(input)); err != nil {
+			t.Errorf("Expected valid but got invalid for %s: %s", input, err)
+			continue
+		}
+		if out.String() != expected {
+			t.Errorf("Expected %s, Got : %s", expected, out.String())
+		}
+		if err := Validate(strings.NewReader(out.String())); err != nil {
+			t.Errorf("Expected the output to be JSON, Got : %s", err)
+		}
+	}
+}
+func TestJSON5ToJSONInvalid(t *testing.T) {
+
+	inputs := map[string]string{
+		"[1, NaN]":       "1:5: Error Converting JSON5. NaN has no equivalent in JSON",
+		"{a: -Infinity}": "1:5: Error Converting JSON5. -Infinity has no equivalent in JSON",
+		"{a: 1 b: 2}":    "1:7: Error Parsing JSON. Expected } but got b",
+		"[1, 2,, 3]":     "1:7: Error Parsing JSON. Expected value but got ,",
+	}
+	for input, expected := range inputs {
+		var out bytes.Buffer
+		err := JSON5ToJSON(&out, strings.NewReader(input))
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %s, Got : %v", expected, input, err)
+		}
+	}
+}
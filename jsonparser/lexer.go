@@ -0,0 +1,750 @@
+package jsonparser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	eof              = -1 // returned by read and peek once the input is used up
+	invalidUTF8      = -2 // returned by read for a byte that isn't valid UTF-8
+	lineContinuation = -3 // returned by lexEscape for a JSON5 escaped line break, which adds nothing to the string
+)
+
+// Reads JSON tokens from an io.Reader one at a time.
+// Only the token being lexed is held in memory, never the whole input
+type Lexer struct {
+	reader  *bufio.Reader
+	options Options
+	pos     Position
+	start   Position // where the token being lexed starts
+	text    []byte   // the token being lexed exactly as it appears in the input
+	// the token being lexed is stopped with a textCode error once its text is longer than
+	// maxText, when it's set, so that one huge string or number can't use up the memory
+	maxText  int
+	textCode ErrorCode
+	// set once the input goes past one of the limits in Options, after which every read fails
+	err error
+}
+
+// Returns a lexer for r with the default options
+func NewLexer(r io.Reader) *Lexer {
+	return Options{}.NewLexer(r)
+}
+func (o Options) NewLexer(r io.Reader) *Lexer {
+	if o.MaxInputBytes > 0 {
+		r = &limitReader{r: r, n: o.MaxInputBytes}
+	}
+	return &Lexer{reader: bufio.NewReader(r), options: o, pos: Position{Offset: 0, Line: 1, Column: 1}}
+}
+
+var errInputTooLarge = errors.New("input too large")
+
+// Reads the first n bytes of r, then fails with errInputTooLarge if there are any more.
+// Once it has failed it doesn't read from r again
+type limitReader struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, errInputTooLarge
+	}
+	// one byte past the limit is enough to tell whether there is more
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) <= l.n {
+		l.n -= int64(n)
+		return n, err
+	}
+	l.exceeded = true
+	return int(l.n), errInputTooLarge
+}
+
+// Function to extract all JSON tokens from a buffer with the default options. The last token is always EOF
+func Lex(buf *bytes.Buffer) ([]Token, error) {
+	return Options{}.Lex(buf)
+}
+
+// Extracts all the tokens from a buffer. The last token is always EOF, which doesn't count
+// towards Options.MaxTokens
+func (o Options) Lex(buf *bytes.Buffer) ([]Token, error) {
+
+	lexer := o.NewLexer(buf)
+	tokens := []Token{}
+	for {
+		token, err := lexer.Next()
+		if err != nil {
+			return nil, err
+		}
+		if token.Kind != EOF && o.MaxTokens > 0 && len(tokens) >= o.MaxTokens {
+			err := tooManyTokens(token, o.MaxTokens)
+			err.Lexing = true
+			return nil, err
+		}
+		tokens = append(tokens, token)
+		if token.Kind == EOF {
+			return tokens, nil
+		}
+	}
+}
+
+// Returns the next token. Once the input is used up every call returns an EOF token
+func (l *Lexer) Next() (Token, error) {
+
+	l.maxText = 0
+	//skip spaces that do not exist within a string, and comments in JSONC and JSON5 mode
+	for {
+		char, err := l.peek()
+		if err != nil {
+			return Token{}, err
+		}
+		if isWhitespace(char) || (l.options.JSON5 && isJSON5Whitespace(char)) {
+			l.skip()
+			continue
+		}
+		if char != '/' || !(l.options.JSONC || l.options.JSON5) {
+			break
+		}
+		if next, err := l.reader.Peek(2); err != nil || (next[1] != '/' && next[1] != '*') {
+			// a lone slash is reported as an unexpected character
+			break
+		}
+		l.text = l.text[:0]
+		l.start = l.pos
+		if err := l.lexComment(); err != nil {
+			return Token{}, err
+		}
+		if l.options.KeepComments {
+			text := string(l.text)
+			return Token{Kind: COMMENT, Text: text, Value: text, Pos: l.start, End: l.pos}, nil
+		}
+	}
+	l.text = l.text[:0]
+	l.start = l.pos
+	token := Token{Pos: l.pos}
+	char, err := l.read()
+	if err != nil {
+		return Token{}, err
+	}
+	if kind, ok := punctuation[char]; ok {
+		token.Kind = kind
+	} else if char == eof {
+		token.Kind = EOF
+	} else if char == '"' || (l.options.JSON5 && char == '\'') {
+		token.Kind = STRING
+		l.limitText(StringTooLong, l.options.MaxStringLength, 2)
+		token.Value, err = l.lexString(token.Pos, char)
+	} else if char == '-' || isDigit(char) || (l.options.JSON5 && (char == '+' || char == '.')) {
+		token.Kind = NUMBER
+		l.limitText(NumberTooLong, l.options.MaxNumberLength, 0)
+		err = l.lexNumber()
+	} else if l.options.JSON5 && (isIdentifierStart(char) || char == '\\') {
+		l.limitText(StringTooLong, l.options.MaxStringLength, 0)
+		token.Value, err = l.lexIdentifier(char)
+		if kind, ok := keywords[string(l.text)]; ok {
+			token.Kind = kind
+		} else if string(l.text) == "Infinity" || string(l.text) == "NaN" {
+			token.Kind = NUMBER
+		} else {
+			token.Kind = IDENTIFIER
+		}
+	} else if isLetter(char) {
+		err = l.lexWord()
+		kind, ok := keywords[string(l.text)]
+		if err == nil && !ok {
+			return Token{}, l.error(InvalidLiteral, token.Pos, fmt.Sprintf("Unexpected %s", l.text))
+		}
+		token.Kind = kind
+	} else {
+		return Token{}, l.error(UnexpectedCharacter, token.Pos, fmt.Sprintf("Unexpected character %q", l.text))
+	}
+	if err != nil {
+		return Token{}, err
+	}
+	token.Text = string(l.text)
+	if token.Kind != STRING && token.Kind != IDENTIFIER {
+		token.Value = token.Text
+	}
+	token.End = l.pos
+	return token, nil
+}
+
+// Reads the rest of a string after the opening quote and returns its decoded value. quote is
+// " or, in JSON5 mode, '. start is where the string starts and is used to point errors at the offending character.
+// A control character such as a raw newline or a bad escape is an error, but scanning carries on
+// so that a string that is never closed is reported where it started rather than at the end of
+// its first line, and so that lexing can resume after the string
+func (l *Lexer) lexString(start Position, quote rune) (string, error) {
+	value := []byte{}
+	var controlErr *SyntaxError
+	for {
+		pos := l.pos
+		char, err := l.read()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case char == eof:
+			err := l.error(UnterminatedString, start, "Unterminated string")
+			err.Expected, err.Actual = string(quote), EOF.String()
+			return "", err
+		case char == quote:
+			if controlErr != nil {
+				return "", controlErr
+			}
+			return string(value), nil
+		case char == '\\':
+			decoded, err := l.lexEscape(pos)
+			var syntaxErr *SyntaxError
+			if err != nil && !errors.As(err, &syntaxErr) {
+				return "", err
+			}
+			if err != nil && controlErr == nil {
+				controlErr = syntaxErr
+			}
+			if decoded != lineContinuation {
+				value = utf8.AppendRune(value, decoded)
+			}
+		case char == invalidUTF8:
+			if controlErr == nil {
+				controlErr = l.error(InvalidUTF8, pos, "Invalid UTF-8 in string")
+			}
+		case char < 0x20 && (!l.options.JSON5 || char == '\n' || char == '\r'):
+			// JSON5 only rules out line breaks
+			if controlErr == nil {
+				controlErr = l.error(ControlCharacter, pos, fmt.Sprintf("Unescaped control character %U in string", char))
+			}
+		default:
+			value = utf8.AppendRune(value, char)
+		}
+	}
+}
+
+var escapes = map[rune]rune{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+// Decodes the rest of an escape sequence after the backslash at pos.
+// A \u escape for a high surrogate must be followed by one for a low surrogate and the two are combined
+func (l *Lexer) lexEscape(pos Position) (rune, error) {
+	char, err := l.read()
+	if err != nil {
+		return 0, err
+	}
+	if decoded, ok := escapes[char]; ok {
+		return decoded, nil
+	}
+	if char == eof {
+		return 0, l.error(InvalidEscape, pos, "Unterminated escape sequence")
+	}
+	if l.options.JSON5 && char != 'u' {
+		if decoded, ok, err := l.lexJSON5Escape(pos, char); ok || err != nil {
+			return decoded, err
+		}
+	}
+	if char != 'u' {
+		return 0, l.error(InvalidEscape, pos, fmt.Sprintf("Invalid escape sequence \\%c", char))
+	}
+	code, err := l.lexUnicodeEscape(pos)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case utf16.IsSurrogate(code) && code >= 0xDC00:
+		return 0, l.error(LoneSurrogate, pos, fmt.Sprintf("Lone low surrogate \\u%04X", code))
+	case utf16.IsSurrogate(code):
+		if next, err := l.peek(); err != nil || next != '\\' {
+			return 0, l.error(LoneSurrogate, pos, fmt.Sprintf("Lone high surrogate \\u%04X", code))
+		}
+		l.read()
+		lowPos := l.pos
+		if next, err := l.read(); err != nil || next != 'u' {
+			return 0, l.error(LoneSurrogate, pos, fmt.Sprintf("Lone high surrogate \\u%04X", code))
+		}
+		low, err := l.lexUnicodeEscape(lowPos)
+		if err != nil || low < 0xDC00 || low > 0xDFFF {
+			return 0, l.error(LoneSurrogate, pos, fmt.Sprintf("Lone high surrogate \\u%04X", code))
+		}
+		return utf16.DecodeRune(code, low), nil
+	}
+	return code, nil
+}
+
+// Decodes the escapes JSON5 adds to JSON, given the character after the backslash at pos.
+// ok is false for the ones it still doesn't allow: digits other than a lone \0
+func (l *Lexer) lexJSON5Escape(pos Position, char rune) (decoded rune, ok bool, err error) {
+	switch {
+	case char == '\'':
+		return '\'', true, nil
+	case char == 'v':
+		return '\v', true, nil
+	case char == '0':
+		next, err := l.peek()
+		return 0, err == nil && !isDigit(next), err
+	case char == 'x':
+		digits := ""
+		for range 2 {
+			next, err := l.peek()
+			if err != nil {
+				return 0, false, err
+			}
+			if !isHexDigit(next) {
+				break
+			}
+			l.read()
+			digits += string(next)
+		}
+		if len(digits) != 2 {
+			return 0, false, l.error(InvalidEscape, pos, fmt.Sprintf("Invalid hex escape \\x%s", digits))
+		}
+		code, _ := strconv.ParseUint(digits, 16, 8)
+		return rune(code), true, nil
+	case char == '\r':
+		// \r\n is a single line break
+		if next, err := l.peek(); err != nil || next != '\n' {
+			return lineContinuation, true, err
+		}
+		l.read()
+		return lineContinuation, true, nil
+	case char == '\n' || char == '\u2028' || char == '\u2029':
+		return lineContinuation, true, nil
+	case isDigit(char) || char == invalidUTF8:
+		return 0, false, nil
+	}
+	// any other character stands for itself
+	return char, true, nil
+}
+
+// Reads the rest of a JSON5 identifier after its first character and returns its name
+// with any \u escapes decoded
+func (l *Lexer) lexIdentifier(first rune) (string, error) {
+	name := []rune{}
+	pos, char := l.start, first
+	for {
+		if char == '\\' {
+			next, err := l.read()
+			if err != nil {
+				return "", err
+			}
+			if next != 'u' {
+				return "", l.error(InvalidEscape, pos, "Expected a \\u escape in identifier")
+			}
+			if char, err = l.lexUnicodeEscape(pos); err != nil {
+				return "", err
+			}
+		}
+		if !isIdentifierPart(char) || (len(name) == 0 && !isIdentifierStart(char)) {
+			return "", l.error(UnexpectedCharacter, pos, fmt.Sprintf("Unexpected character %q in identifier", char))
+		}
+		name = append(name, char)
+		next, err := l.peek()
+		if err != nil {
+			return "", err
+		}
+		if next != '\\' && !isIdentifierPart(next) {
+			return string(name), nil
+		}
+		pos = l.pos
+		l.read()
+		char = next
+	}
+}
+
+// Reads the four hex digits of a \uXXXX escape at pos and returns the UTF-16 code unit.
+// Stops early at anything that isn't a hex digit so it isn't taken from the rest of the string
+func (l *Lexer) lexUnicodeEscape(pos Position) (rune, error) {
+	digits := []rune{}
+	for range 4 {
+		char, err := l.peek()
+		if err != nil {
+			return 0, err
+		}
+		if !isHexDigit(char) {
+			break
+		}
+		l.read()
+		digits = append(digits, char)
+	}
+	code, err := strconv.ParseUint(string(digits), 16, 16)
+	if err != nil || len(digits) != 4 {
+		return 0, l.error(InvalidEscape, pos, fmt.Sprintf("Invalid unicode escape \\u%s", string(digits)))
+	}
+	return rune(code), nil
+}
+
+// Reads a // comment up to the end of the line, leaving the line break, or a /* */ comment.
+// A line ends at a line feed or carriage return, and in JSON5 also at U+2028 or U+2029
+func (l *Lexer) lexComment() error {
+	l.read()
+	slash, _ := l.read()
+	prev := rune(0)
+	for {
+		char, err := l.peek()
+		if err != nil {
+			return err
+		}
+		switch {
+		case slash == '/' && (l.isLineTerminator(char) || char == eof):
+			return nil
+		case char == eof:
+			return &SyntaxError{
+				Code:     UnterminatedComment,
+				Lexing:   true,
+				Expected: "*/",
+				Actual:   EOF.String(),
+				Pos:      l.start,
+				End:      l.pos,
+				Msg:      "Unterminated comment",
+			}
+		}
+		if l.options.KeepComments {
+			l.read()
+		} else {
+			// the comment is dropped, so it's streamed past rather than held in memory
+			l.skip()
+		}
+		if slash == '*' && prev == '*' && char == '/' {
+			return nil
+		}
+		prev = char
+	}
+}
+
+// Reads the rest of a number following the RFC 8259 grammar:
+//
+//	number = [ minus ] int [ frac ] [ exp ]
+//	int    = zero / ( digit1-9 *DIGIT )
+//	frac   = decimal-point 1*DIGIT
+//	exp    = e [ minus / plus ] 1*DIGIT
+//
+// JSON5 mode also allows a + sign, a leading or trailing decimal point, hex numbers, Infinity and NaN.
+// Errors point at the first character that doesn't fit the grammar
+func (l *Lexer) lexNumber() error {
+	first := rune(l.text[0])
+	if first == '-' || first == '+' {
+		char, err := l.peek()
+		if err != nil {
+			return err
+		}
+		if l.options.JSON5 && isIdentifierStart(char) {
+			if err := l.lexWord(); err != nil {
+				return err
+			}
+			if word := string(l.text[1:]); word != "Infinity" && word != "NaN" {
+				return l.error(InvalidNumber, l.start, fmt.Sprintf("Invalid number %s", l.text))
+			}
+			return nil
+		}
+		if !isDigit(char) && !(l.options.JSON5 && char == '.') {
+			return l.numberError("Expected a digit after the sign")
+		}
+		first, _ = l.read()
+	}
+	switch {
+	case first == '.':
+		// only reachable in JSON5 mode
+		if err := l.lexRequiredDigits("Expected a digit after the decimal point"); err != nil {
+			return err
+		}
+	case first == '0':
+		char, err := l.peek()
+		if err != nil {
+			return err
+		}
+		if l.options.JSON5 && (char == 'x' || char == 'X') {
+			return l.lexHexNumber()
+		}
+		if isDigit(char) {
+			return l.numberError("Leading zeros are not allowed in numbers")
+		}
+	default:
+		if _, err := l.lexDigits(); err != nil {
+			return err
+		}
+	}
+	if first != '.' {
+		point, err := l.accept(".")
+		if err != nil {
+			return err
+		}
+		if point && l.options.JSON5 {
+			// JSON5 allows a trailing decimal point
+			_, err = l.lexDigits()
+		} else if point {
+			err = l.lexRequiredDigits("Expected a digit after the decimal point")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	exponent, err := l.accept("eE")
+	if err != nil {
+		return err
+	}
+	if exponent {
+		if _, err := l.accept("+-"); err != nil {
+			return err
+		}
+		if err := l.lexRequiredDigits("Expected a digit in the exponent"); err != nil {
+			return err
+		}
+	}
+	return l.lexNumberEnd()
+}
+
+// Reads the hex digits of a JSON5 number after the 0
+func (l *Lexer) lexHexNumber() error {
+	l.read()
+	digits := 0
+	for {
+		char, err := l.peek()
+		if err != nil {
+			return err
+		}
+		if !isHexDigit(char) {
+			break
+		}
+		l.read()
+		digits++
+	}
+	if digits == 0 {
+		return l.numberError("Expected a hex digit after 0x")
+	}
+	return l.lexNumberEnd()
+}
+
+// Checks that the number read so far isn't followed by more of what looks like a number,
+// as in 1.5.3, 1_000 or 12abc
+func (l *Lexer) lexNumberEnd() error {
+	char, err := l.peek()
+	if err != nil {
+		return err
+	}
+	if isNumberPart(char) {
+		return l.numberError(fmt.Sprintf("Unexpected %q in number", char))
+	}
+	return nil
+}
+
+// Reads a run of decimal digits and returns how many there were
+func (l *Lexer) lexDigits() (int, error) {
+	n := 0
+	for {
+		char, err := l.peek()
+		if err != nil || !isDigit(char) {
+			return n, err
+		}
+		l.read()
+		n++
+	}
+}
+
+// Reads a run of at least one decimal digit, returning an error with message if there are none
+func (l *Lexer) lexRequiredDigits(message string) error {
+	n, err := l.lexDigits()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return l.numberError(message)
+	}
+	return nil
+}
+
+// Reads the next character if it is one of chars and reports whether it did
+func (l *Lexer) accept(chars string) (bool, error) {
+	char, err := l.peek()
+	if err != nil || !strings.ContainsRune(chars, char) {
+		return false, err
+	}
+	l.read()
+	return true, nil
+}
+
+// Returns an InvalidNumber error for the character up next, then reads the rest of what looks
+// like the number so that lexing can resume after it
+func (l *Lexer) numberError(message string) error {
+	pos := l.pos
+	char, err := l.peek()
+	if err != nil {
+		return err
+	}
+	if isNumberPart(char) || char == '+' || char == '-' {
+		l.read()
+	}
+	numErr := l.error(InvalidNumber, pos, message)
+	for {
+		char, err := l.peek()
+		if err != nil {
+			return err
+		}
+		signed := (char == '+' || char == '-') && isExponent(rune(l.text[len(l.text)-1]))
+		if !isNumberPart(char) && !signed {
+			return numErr
+		}
+		l.read()
+	}
+}
+
+// Reads the rest of a run of letters
+func (l *Lexer) lexWord() error {
+	for {
+		char, err := l.peek()
+		if err != nil {
+			return err
+		}
+		if !isLetter(char) {
+			return nil
+		}
+		l.read()
+	}
+}
+
+// Reads the next character into the token text and moves the position past it.
+// Returns eof at the end of the input
+func (l *Lexer) read() (rune, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	char, width, err := l.reader.ReadRune()
+	if err == io.EOF {
+		return eof, nil
+	}
+	if err != nil {
+		return 0, l.readError(err)
+	}
+	if char == utf8.RuneError && width == 1 {
+		// keep the byte as it is in the text instead of the replacement character
+		l.reader.UnreadRune()
+		b, _ := l.reader.ReadByte()
+		l.text = append(l.text, b)
+		l.pos = l.pos.add(1)
+		char = invalidUTF8
+	} else {
+		l.text = utf8.AppendRune(l.text, char)
+		if char == '\n' {
+			l.pos = Position{Offset: l.pos.Offset + 1, Line: l.pos.Line + 1, Column: 1}
+		} else {
+			l.pos = l.pos.add(width)
+		}
+	}
+	if l.maxText > 0 && len(l.text) > l.maxText {
+		l.err = l.tooLong()
+		return 0, l.err
+	}
+	return char, nil
+}
+
+// Reads the next character, which has already been peeked at, without keeping it in the token
+// text, so that whitespace between tokens isn't held in memory however much of it there is
+func (l *Lexer) skip() {
+	l.read()
+	l.text = l.text[:0]
+}
+
+// Returns the next character without reading it
+func (l *Lexer) peek() (rune, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	char, _, err := l.reader.ReadRune()
+	if err == io.EOF {
+		return eof, nil
+	}
+	if err != nil {
+		return 0, l.readError(err)
+	}
+	return char, l.reader.UnreadRune()
+}
+
+// Limits the text of the token being lexed to max characters plus extra for its quotes.
+// Going past it is a code error. A max of 0 is no limit
+func (l *Lexer) limitText(code ErrorCode, max, extra int) {
+	l.maxText, l.textCode = 0, code
+	if max > 0 {
+		l.maxText = max + extra
+	}
+}
+
+// Returns the error for a token that is longer than limitText allows
+func (l *Lexer) tooLong() *SyntaxError {
+	message := fmt.Sprintf("Strings can't be longer than %d bytes", l.options.MaxStringLength)
+	if l.textCode == NumberTooLong {
+		message = fmt.Sprintf("Numbers can't be longer than %d characters", l.options.MaxNumberLength)
+	}
+	return l.error(l.textCode, l.start, message)
+}
+
+// Returns an error for the text read since pos, which must be within the current token
+func (l *Lexer) error(code ErrorCode, pos Position, message string) *SyntaxError {
+	actual := string(l.text[pos.Offset-l.start.Offset:])
+	return &SyntaxError{Code: code, Lexing: true, Actual: actual, Pos: pos, End: l.pos, Msg: message}
+}
+
+func (l *Lexer) readError(err error) error {
+	if err == errInputTooLarge {
+		l.err = &SyntaxError{
+			Code:   InputTooLarge,
+			Lexing: true,
+			Pos:    l.pos,
+			End:    l.pos,
+			Msg:    fmt.Sprintf("The input is larger than %d bytes", l.options.MaxInputBytes),
+		}
+		return l.err
+	}
+	return fmt.Errorf("%s: Error reading JSON. %w", l.pos, err)
+}
+
+// JSON only allows space, tab, line feed and carriage return between tokens
+func isWhitespace(char rune) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+// Reports whether char ends a line, for // comments
+func (l *Lexer) isLineTerminator(char rune) bool {
+	return char == '\n' || char == '\r' || (l.options.JSON5 && (char == '\u2028' || char == '\u2029'))
+}
+
+// The whitespace JSON5 allows on top of JSON's
+func isJSON5Whitespace(char rune) bool {
+	switch char {
+	case '\v', '\f', '\u00A0', '\u2028', '\u2029', '\uFEFF':
+		return true
+	}
+	return char >= 0 && unicode.Is(unicode.Zs, char)
+}
+
+// JSON5 identifiers follow ECMAScript's IdentifierName
+func isIdentifierStart(char rune) bool {
+	return char == '$' || char == '_' || (char >= 0 && (unicode.IsLetter(char) || unicode.Is(unicode.Nl, char)))
+}
+func isIdentifierPart(char rune) bool {
+	return isIdentifierStart(char) || char == '\u200C' || char == '\u200D' ||
+		(char >= 0 && unicode.In(char, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc))
+}
+func isDigit(char rune) bool {
+	return char >= '0' && char <= '9'
+}
+func isHexDigit(char rune) bool {
+	return isDigit(char) || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')
+}
+func isLetter(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || char == '_' || isDigit(char)
+}
+func isNumberPart(char rune) bool {
+	return isLetter(char) || char == '.'
+}
+func isExponent(char rune) bool {
+	return (char == 'e' || char == 'E')
+}
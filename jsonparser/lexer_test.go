@@ -0,0 +1,812 @@
+package jsonparser
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTrueBoolean(t *testing.T) {
+
+	buffer := bytes.NewBufferString(
+		`{
+    "true": true,
+    }`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[1]) != 4 {
+		t.Errorf("Expected 4 token on Line 2, Got : %d", len(tokens[1]))
+	}
+}
+func TestFalseBoolean(t *testing.T) {
+
+	buffer := bytes.NewBufferString(
+		`{
+    "false": false,
+    }`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[1]) != 4 {
+		t.Errorf("Expected 4 token on Line 2, Got : %d", len(tokens[1]))
+	}
+}
+func TestNull(t *testing.T) {
+
+	buffer := bytes.NewBufferString(
+		`{
+    "null": null,
+    }`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[1]) != 4 {
+		t.Errorf("Expected 4 token on Line 2, Got : %d", len(tokens[1]))
+	}
+}
+func TestSingleLineObjectWithSpacesBetweenValues(t *testing.T) {
+	buffer := bytes.NewBufferString(`{"hey":" null", "how far":      "i dey", "key2": "time"}`)
+	tokens := mustLex(t, buffer)
+	numLines := len(tokens)
+	numTokens := len(tokens[0])
+	if numLines != 1 {
+		t.Errorf("Expected one line of tokens. Got %d", numLines)
+	}
+	if numTokens != 13 {
+		t.Errorf("Expected 13 tokens. Got: %d", numTokens)
+	}
+}
+func TestKeyWithSpaceInsideString(t *testing.T) {
+	buffer := bytes.NewBufferString(`{"a key": "value"}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestValueWithSpaceInsideString(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": "a value"}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestIntegerValueWithSpaceBefore(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": 4}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestIntegerValueWithoutSpaceBefore(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key":4}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestFloatValueWithoutSpaceBefore(t *testing.T) {
+	buffer := bytes.NewBufferString(`{"key": 4.5}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestFloatValueWithSpaceBefore(t *testing.T) {
+	buffer := bytes.NewBufferString(`{"key":4.5}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestValueStringWithSpecialCharacters(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": "!£$%^&*()_+{}[,].:@~;'#\\|-+-="`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 4 {
+		t.Errorf("Expected 4 tokens, Got : %d", len(tokens[0]))
+	}
+
+}
+func TestValueStringWithEscapedCharacters(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": "the boy said to me \" my friend where art thou? \""`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 4 {
+		t.Errorf("Expected 4 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestValueWithExponent(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": 10e1}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestValueWithPositiveExponent(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": 10e+10}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+
+}
+func TestValueWithNegativeExponent(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": 10e-10}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+
+}
+
+func TestValueWithUpperCaseExponent(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": 10E66}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 5 {
+		t.Errorf("Expected 5 tokens, Got : %d", len(tokens[0]))
+	}
+
+}
+func TestSingleLineNestedObjects(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": {"nested key": {"nested key 2": "value"}}}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 13 {
+		t.Errorf("Expected 13 tokens, Got : %d", len(tokens[0]))
+	}
+
+}
+func TestMultilineLineNestedObjects(t *testing.T) {
+
+	buffer := bytes.NewBufferString(
+		`{
+    "key": {
+      "nested key": {
+          "nested key 2": "value",
+          "nested key 3": true
+          }
+      }
+    }`)
+	tokens := mustLex(t, buffer)
+	if len(tokens) != 8 {
+		t.Errorf("Expected 8 lines. Got: %d", len(tokens))
+	}
+	if len(tokens[0]) != 1 {
+		t.Errorf("Expected 1 token on Line 1, Got : %d", len(tokens[0]))
+	}
+	if len(tokens[1]) != 3 {
+		t.Errorf("Expected 3 token on Line 2, Got : %d", len(tokens[1]))
+	}
+	if len(tokens[2]) != 3 {
+		t.Errorf("Expected 3 token on Line 3, Got : %d", len(tokens[2]))
+	}
+	if len(tokens[3]) != 4 {
+		t.Errorf("Expected 4 tokens on Line 4, Got : %d", len(tokens[3]))
+	}
+	if len(tokens[4]) != 3 {
+		t.Errorf("Expected 3 token on Line 5, Got : %d", len(tokens[4]))
+	}
+	if len(tokens[5]) != 1 {
+		t.Errorf("Expected 1 token on Line 6, Got : %d", len(tokens[5]))
+	}
+	if len(tokens[6]) != 1 {
+		t.Errorf("Expected 1 token on Line 7, Got : %d", len(tokens[6]))
+	}
+
+	if len(tokens[7]) != 1 {
+		t.Errorf("Expected 1 token on Line 8, Got : %d", len(tokens[6]))
+	}
+}
+func TestArrayContainingFloats(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": [2.5, 4.2, 5.555423423, 3.4124123]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 13 {
+		t.Errorf("Expected 13 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestArrayContainingPositiveIntegers(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": [2, 4, 5, 3]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 13 {
+		t.Errorf("Expected 13 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestArrayContainingNegativeIntegers(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": [-2, -4, 5, -3]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 13 {
+		t.Errorf("Expected 13 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestArrayContainingFloatsAndIntegers(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": [2.5,33, 4.2, 75, 5.555423423, 60, 3.4124123, 10]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 21 {
+		t.Errorf("Expected 21 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestArrayContainingStrings(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": ["string1", "string2", "string3"]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 11 {
+		t.Errorf("Expected 11 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestArrayContainingStringsAndFloats(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": ["string1", 5.233, "string2", 7.15, "string3", 2.55552]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 17 {
+		t.Errorf("Expected 17 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestArrayContainingStringsAndIntegers(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": ["string1", 5, "string2", 71, "string3", 44]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 17 {
+		t.Errorf("Expected 17 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestArrayContainingSingleObject(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": [{"key2":"value"}]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 11 {
+		t.Errorf("Expected 11 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestArrayContainingMultipleObjects(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"key": [{"key2":"value"}, {"key3":"value2"}]}`)
+	tokens := mustLex(t, buffer)
+	if len(tokens[0]) != 17 {
+		t.Errorf("Expected 17 tokens, Got : %d", len(tokens[0]))
+	}
+}
+func TestMultilineObjectWithArrayElement(t *testing.T) {
+
+	buffer := bytes.NewBufferString(
+		`{
+    "key": [
+            {"key2":"value"}, {"key3":"value2"}
+            ]
+    }`)
+	tokens := mustLex(t, buffer)
+	if len(tokens) != 5 {
+		t.Errorf("Expected 5 lines. Got: %d", len(tokens))
+	}
+	if len(tokens[0]) != 1 {
+		t.Errorf("Expected 1 token on Line 1, Got : %d", len(tokens[0]))
+	}
+
+	if len(tokens[1]) != 3 {
+		t.Errorf("Expected 3 token on Line 1, Got : %d", len(tokens[1]))
+	}
+	if len(tokens[2]) != 11 {
+		t.Errorf("Expected 11 token on Line 1, Got : %d", len(tokens[2]))
+	}
+	if len(tokens[3]) != 1 {
+		t.Errorf("Expected 1 token on Line 1, Got : %d", len(tokens[3]))
+	}
+	if len(tokens[4]) != 1 {
+		t.Errorf("Expected 1 token on Line 1, Got : %d", len(tokens[4]))
+	}
+}
+func TestMultilineArrayWithMultipleElements(t *testing.T) {
+
+	buffer := bytes.NewBufferString(
+		`{"key": [
+        {"key2":"value"},
+        {"key3":"value2"}
+      ]
+    }`)
+	tokens := mustLex(t, buffer)
+	if len(tokens) != 5 {
+		t.Errorf("Expected 5 lines. Got: %d", len(tokens))
+	}
+	if len(tokens[0]) != 4 {
+		t.Errorf("Expected 4 token on Line 1, Got : %d", len(tokens[0]))
+	}
+
+	if len(tokens[1]) != 6 {
+		t.Errorf("Expected 6 token on Line 2, Got : %d", len(tokens[1]))
+	}
+	if len(tokens[2]) != 5 {
+		t.Errorf("Expected 5 token on Line 3, Got : %d", len(tokens[2]))
+	}
+	if len(tokens[3]) != 1 {
+		t.Errorf("Expected 1 token on Line 4, Got : %d", len(tokens[3]))
+	}
+	if len(tokens[4]) != 1 {
+		t.Errorf("Expected 1 token on Line 5, Got : %d", len(tokens[4]))
+	}
+}
+
+func TestComplexObjectWithEdgeCases(t *testing.T) {
+	buffer := bytes.NewBufferString(
+		`[
+    "JSON Test Pattern pass1",
+    {"object with 1 member":["array with 1 element"]},
+    {},
+    [],
+    -42,
+    true,
+    false,
+    null,
+    {
+        "integer": 1234567890,
+        "real": -9876.543210,
+        "e": 0.123456789e-12,
+        "E": 1.234567890E+34,
+        "":  23456789012E66,
+        "zero": 0,
+        "one": 1,
+        "space": " ",
+        "quote": "\"",
+        "backslash": "\\",
+        "controls": "\b\f\n\r\t",
+        "slash": "/ & \/",
+        "alpha": "abcdefghijklmnopqrstuvwyz",
+        "ALPHA": "ABCDEFGHIJKLMNOPQRSTUVWYZ",
+        "digit": "0123456789",
+        "0123456789": "digit",
+        "special": "1~!@#$%^&*()_+-={':[,]}|;.</>?",
+        "hex": "\u0123\u4567\u89AB\uCDEF\uabcd\uef4A",
+        "true": true,
+        "false": false,
+        "null": null,
+        "array":[  ],
+        "object":{  },
+        "address": "50 St. James Street",
+        "url": "http://www.JSON.org/",
+        "comment": "// /* <!-- --",
+        "# -- --> */": " ",
+        " s p a c e d " :[1,2 , 3
+
+,
+
+4 , 5        ,          6           ,7        ],"compact":[1,2,3,4,5,6,7],
+        "jsontext": "{\"object with 1 member\":[\"array with 1 element\"]}",
+        "quotes": "&#34; \u0022 %22 0x22 034 &#x22;",
+        "\/\\\"\uCAFE\uBABE\uAB98\uFCDE\ubcda\uef4A\b\f\n\r\t1~!@#$%^&*()_+-=[]{}|;:',./<>?"
+: "A key can be any string"
+    },
+    0.5 ,98.6
+,
+99.44
+,
+
+1066,
+1e1,
+0.1e1,
+1e-1,
+1e00,2e+00,2e-00
+,"rosebud"]`)
+
+	tokens := mustLex(t, buffer)
+	if len(tokens) != 58 {
+		t.Errorf("Expected 58 lines. Got: %d", len(tokens))
+	}
+	tokenCount := []int{1, 2, 8, 3, 3, 2, 2, 2, 2, 1, 4, 4, 4, 4, 4, 4, 4, 4, 4,
+		4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 5, 5, 4, 4, 4, 4,
+		8, 0, 1, 0, 27, 4, 4, 1, 2, 2, 3, 1, 1, 1, 0, 2, 2, 2, 2, 5, 3}
+	for i := 0; i < len(tokenCount); i++ {
+
+		if len(tokens[i]) != tokenCount[i] {
+			t.Errorf("Expected %d token on Line %d, Got : %d", tokenCount[i], i+1, len(tokens[i]))
+		}
+	}
+
+}
+func TestTokenPositions(t *testing.T) {
+
+	buffer := bytes.NewBufferString("{\n  \"key\": 10,\r\n  \"é\": true\n}")
+	tokens := mustLex(t, buffer)
+	expected := [][]Position{
+		{{0, 1, 1}},
+		{{4, 2, 3}, {9, 2, 8}, {11, 2, 10}, {13, 2, 12}},
+		{{18, 3, 3}, {22, 3, 7}, {24, 3, 9}},
+		{{29, 4, 1}},
+	}
+	for line, positions := range expected {
+		if len(tokens[line]) != len(positions) {
+			t.Fatalf("Expected %d tokens on Line %d, Got : %d", len(positions), line+1, len(tokens[line]))
+		}
+		for i, pos := range positions {
+			if tokens[line][i].Pos != pos {
+				t.Errorf("Expected %s at %+v, Got : %+v", tokens[line][i].Text, pos, tokens[line][i].Pos)
+			}
+		}
+	}
+}
+
+// Lexes buffer and groups the tokens by the line they start on, leaving out EOF
+func mustLex(t *testing.T, buffer *bytes.Buffer) [][]Token {
+	t.Helper()
+	tokens, err := Lex(buffer)
+	if err != nil {
+		t.Fatalf("Expected no lexer error, Got : %s", err)
+	}
+	eof := tokens[len(tokens)-1]
+	if eof.Kind != EOF {
+		t.Fatalf("Expected last token to be EOF, Got : %s", eof)
+	}
+	lines := make([][]Token, eof.Pos.Line)
+	for _, token := range tokens[:len(tokens)-1] {
+		lines[token.Pos.Line-1] = append(lines[token.Pos.Line-1], token)
+	}
+	return lines
+}
+func TestTokenKinds(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`{"true": [-1.5e3, "null", false, null]}`)
+	tokens := mustLex(t, buffer)
+	expected := []Token{
+		{Kind: LEFTCURLYBRACE, Value: "{"},
+		{Kind: STRING, Value: "true"},
+		{Kind: COLON, Value: ":"},
+		{Kind: LEFTSQUAREBRACE, Value: "["},
+		{Kind: NUMBER, Value: "-1.5e3"},
+		{Kind: COMMA, Value: ","},
+		{Kind: STRING, Value: "null"},
+		{Kind: COMMA, Value: ","},
+		{Kind: FALSE, Value: "false"},
+		{Kind: COMMA, Value: ","},
+		{Kind: NULL, Value: "null"},
+		{Kind: RIGHTSQUAREBRACE, Value: "]"},
+		{Kind: RIGHTCURLYBRACE, Value: "}"},
+	}
+	if len(tokens[0]) != len(expected) {
+		t.Fatalf("Expected %d tokens, Got : %d", len(expected), len(tokens[0]))
+	}
+	for i, token := range expected {
+		if tokens[0][i].Kind != token.Kind || tokens[0][i].Value != token.Value {
+			t.Errorf("Expected %s %s, Got : %s %s", token.Kind, token.Value, tokens[0][i].Kind, tokens[0][i].Value)
+		}
+	}
+}
+func TestTokenKindString(t *testing.T) {
+
+	if kind := TokenKind(-1).String(); kind != "TokenKind(-1)" {
+		t.Errorf("Expected TokenKind(-1), Got : %s", kind)
+	}
+	if kind := TokenKind(100).String(); kind != "TokenKind(100)" {
+		t.Errorf("Expected TokenKind(100), Got : %s", kind)
+	}
+}
+func TestUnexpectedCharacter(t *testing.T) {
+
+	for _, input := range []string{`{"key": tru}`, `{"key": 'value'}`, `[1 + 2]`} {
+		if _, err := Lex(bytes.NewBufferString(input)); err == nil {
+			t.Errorf("Expected lexer error for %s", input)
+		}
+	}
+}
+func TestStringEscapes(t *testing.T) {
+
+	buffer := bytes.NewBufferString(`["\"\\\/\b\f\n\r\t", "\u00e9\u4567", "\ud83d\ude00", "\\"]`)
+	tokens := mustLex(t, buffer)
+	expected := []string{"\"\\/\b\f\n\r\t", "\u00e9\u4567", "😀", "\\"}
+	strings := []Token{}
+	for _, token := range tokens[0] {
+		if token.Kind == STRING {
+			strings = append(strings, token)
+		}
+	}
+	if len(strings) != len(expected) {
+		t.Fatalf("Expected %d strings, Got : %d", len(expected), len(strings))
+	}
+	for i, value := range expected {
+		if strings[i].Value != value {
+			t.Errorf("Expected %q, Got : %q", value, strings[i].Value)
+		}
+	}
+}
+func TestInvalidStrings(t *testing.T) {
+
+	inputs := map[string]string{
+		`["\x15"]`:              "1:3: Error Lexing JSON. Invalid escape sequence \\x",
+		`["\u12"]`:              "1:3: Error Lexing JSON. Invalid unicode escape \\u12",
+		`["\u12G4"]`:            "1:3: Error Lexing JSON. Invalid unicode escape \\u12",
+		`["\u"]`:                "1:3: Error Lexing JSON. Invalid unicode escape \\u",
+		"[\"tab\tin string\"]":  "1:6: Error Lexing JSON. Unescaped control character U+0009 in string",
+		`["\ud83d"]`:            "1:3: Error Lexing JSON. Lone high surrogate \\uD83D",
+		`["\ud83d\u0041"]`:      "1:3: Error Lexing JSON. Lone high surrogate \\uD83D",
+		`["a", "\ude00 alone"]`: "1:8: Error Lexing JSON. Lone low surrogate \\uDE00",
+		`["unterminated]`:       "1:2: Error Lexing JSON. Unterminated string",
+	}
+	for input, expected := range inputs {
+		_, err := Lex(bytes.NewBufferString(input))
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %s, Got : %v", expected, input, err)
+		}
+	}
+	// the escape stops at the first character that isn't a hex digit, leaving the closing quote
+	var syntaxErr *SyntaxError
+	if _, err := Lex(bytes.NewBufferString(`["\u12"]`)); !errors.As(err, &syntaxErr) || syntaxErr.Actual != `\u12` {
+		t.Errorf("Expected the error to span \\u12, Got : %+v", syntaxErr)
+	}
+}
+func TestUnterminatedStringAcrossLines(t *testing.T) {
+
+	inputs := map[string]string{
+		"{\n  \"key\": \"value\n}":         "2:10: Error Lexing JSON. Unterminated string",
+		"{\n  \"key\": \"line\nbreak\"\n}": "2:15: Error Lexing JSON. Unescaped control character U+000A in string",
+		"[\n  \"never closed\n\n]":         "2:3: Error Lexing JSON. Unterminated string",
+	}
+	for input, expected := range inputs {
+		_, err := Lex(bytes.NewBufferString(input))
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %q, Got : %v", expected, input, err)
+		}
+	}
+}
+func TestTokensAcrossLines(t *testing.T) {
+
+	buffer := bytes.NewBufferString("[1,\n-2\n,\n3.5e1\n]")
+	tokens, err := Lex(buffer)
+	if err != nil {
+		t.Fatalf("Expected no lexer error, Got : %s", err)
+	}
+	expected := []string{"[", "1", ",", "-2", ",", "3.5e1", "]", ""}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, Got : %d", len(expected), len(tokens))
+	}
+	for i, text := range expected {
+		if tokens[i].Text != text {
+			t.Errorf("Expected %q, Got : %q", text, tokens[i].Text)
+		}
+	}
+	if eof := tokens[len(tokens)-1]; eof.Kind != EOF || eof.Pos != (Position{16, 5, 2}) {
+		t.Errorf("Expected EOF at 5:2, Got : %s at %s", eof.Kind, eof.Pos)
+	}
+}
+func TestLexerReadsIncrementally(t *testing.T) {
+
+	// the reader fails after the first few tokens so lexing must not need the rest of the input
+	reader := io.MultiReader(strings.NewReader(`[1, "two"`), iotest.ErrReader(errors.New("connection reset")))
+	lexer := NewLexer(reader)
+	for _, expected := range []TokenKind{LEFTSQUAREBRACE, NUMBER, COMMA, STRING} {
+		token, err := lexer.Next()
+		if err != nil {
+			t.Fatalf("Expected %s, Got : %s", expected, err)
+		}
+		if token.Kind != expected {
+			t.Errorf("Expected %s, Got : %s", expected, token.Kind)
+		}
+	}
+	if _, err := lexer.Next(); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("Expected read error, Got : %v", err)
+	}
+}
+func TestWhitespaceIsNotKept(t *testing.T) {
+
+	reader := io.MultiReader(strings.NewReader("["), &generatedWhitespace{remaining: 10 << 20}, strings.NewReader("]"))
+	lexer := NewLexer(reader)
+	for _, expected := range []TokenKind{LEFTSQUAREBRACE, RIGHTSQUAREBRACE, EOF} {
+		if token, err := lexer.Next(); err != nil || token.Kind != expected {
+			t.Fatalf("Expected %s, Got : %s %v", expected, token.Kind, err)
+		}
+	}
+	if cap(lexer.text) > 1024 {
+		t.Errorf("Expected the whitespace to be dropped as it is skipped, Got : %d bytes kept", cap(lexer.text))
+	}
+}
+
+// Reads remaining bytes of spaces and line breaks
+type generatedWhitespace struct {
+	remaining int
+}
+
+func (g *generatedWhitespace) Read(p []byte) (int, error) {
+	if g.remaining == 0 {
+		return 0, io.EOF
+	}
+	n := min(len(p), g.remaining)
+	for i := range n {
+		p[i] = " \n\t\r"[i%4]
+	}
+	g.remaining -= n
+	return n, nil
+}
+func TestJSONCComments(t *testing.T) {
+
+	input := "// settings\n{\n  /* the theme */ \"theme\": \"dark\", // trailing\n  \"url\": \"http://example.com/*not a comment*/\"\n}"
+	tokens, err := Options{JSONC: true, KeepComments: true}.Lex(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("Expected no lexer error, Got : %s", err)
+	}
+	comments := []Token{}
+	for _, token := range tokens {
+		if token.Kind == COMMENT {
+			comments = append(comments, token)
+		}
+	}
+	expected := []string{"// settings", "/* the theme */", "// trailing"}
+	if len(comments) != len(expected) {
+		t.Fatalf("Expected %d comments, Got : %v", len(expected), comments)
+	}
+	for i, text := range expected {
+		if comments[i].Text != text {
+			t.Errorf("Expected %q, Got : %q", text, comments[i].Text)
+		}
+	}
+	if comments[1].Pos != (Position{16, 3, 3}) || comments[1].End != (Position{31, 3, 18}) {
+		t.Errorf("Expected comment from 3:3 to 3:18, Got : %s to %s", comments[1].Pos, comments[1].End)
+	}
+
+	tokens, err = Options{JSONC: true}.Lex(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("Expected no lexer error, Got : %s", err)
+	}
+	if len(tokens) != 10 {
+		t.Errorf("Expected comments to be skipped, Got : %v", tokens)
+	}
+	if _, err := Lex(bytes.NewBufferString(input)); err == nil {
+		t.Error("Expected comments to be invalid without JSONC")
+	}
+}
+func TestSkippedCommentsAreNotKept(t *testing.T) {
+
+	reader := io.MultiReader(strings.NewReader("[/*"), &generatedWhitespace{remaining: 10 << 20}, strings.NewReader("*/]"))
+	lexer := Options{JSONC: true}.NewLexer(reader)
+	for _, expected := range []TokenKind{LEFTSQUAREBRACE, RIGHTSQUAREBRACE, EOF} {
+		if token, err := lexer.Next(); err != nil || token.Kind != expected {
+			t.Fatalf("Expected %s, Got : %s %v", expected, token.Kind, err)
+		}
+	}
+	if cap(lexer.text) > 1024 {
+		t.Errorf("Expected the comment to be dropped as it is skipped, Got : %d bytes kept", cap(lexer.text))
+	}
+}
+func TestInvalidJSONCComments(t *testing.T) {
+
+	inputs := map[string]string{
+		"[1, /* never closed ]": "1:5: Error Lexing JSON. Unterminated comment",
+		"[1 / 2]":               "1:4: Error Lexing JSON. Unexpected character \"/\"",
+		"[1]/":                  "1:4: Error Lexing JSON. Unexpected character \"/\"",
+	}
+	for input, expected := range inputs {
+		_, err := Options{JSONC: true}.Lex(bytes.NewBufferString(input))
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %s, Got : %v", expected, input, err)
+		}
+	}
+}
+func TestJSON5Tokens(t *testing.T) {
+
+	input := "{unquoted: 'single \"quoted\"', $_\\u0061bé: +Infinity, line: 'one \\\ntwo', hex: -0x1F, " +
+		"points: [.5, 5., +1e3, NaN], escapes: '\\x41\\v\\0\\'\\q', /* comment */ null: 1,}"
+	tokens, err := Options{JSON5: true}.Lex(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("Expected no lexer error, Got : %s", err)
+	}
+	expected := []struct {
+		kind  TokenKind
+		value string
+	}{
+		{LEFTCURLYBRACE, "{"}, {IDENTIFIER, "unquoted"}, {COLON, ":"}, {STRING, `single "quoted"`}, {COMMA, ","},
+		{IDENTIFIER, "$_abé"}, {COLON, ":"}, {NUMBER, "+Infinity"}, {COMMA, ","},
+		{IDENTIFIER, "line"}, {COLON, ":"}, {STRING, "one two"}, {COMMA, ","},
+		{IDENTIFIER, "hex"}, {COLON, ":"}, {NUMBER, "-0x1F"}, {COMMA, ","},
+		{IDENTIFIER, "points"}, {COLON, ":"}, {LEFTSQUAREBRACE, "["}, {NUMBER, ".5"}, {COMMA, ","}, {NUMBER, "5."},
+		{COMMA, ","}, {NUMBER, "+1e3"}, {COMMA, ","}, {NUMBER, "NaN"}, {RIGHTSQUAREBRACE, "]"}, {COMMA, ","},
+		{IDENTIFIER, "escapes"}, {COLON, ":"}, {STRING, "A\v\x00'q"}, {COMMA, ","},
+		{NULL, "null"}, {COLON, ":"}, {NUMBER, "1"}, {COMMA, ","}, {RIGHTCURLYBRACE, "}"}, {EOF, ""},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, Got : %v", len(expected), tokens)
+	}
+	for i, token := range tokens {
+		if token.Kind != expected[i].kind || token.Value != expected[i].value {
+			t.Errorf("Expected %s %q, Got : %s %q", expected[i].kind, expected[i].value, token.Kind, token.Value)
+		}
+	}
+	if _, err := Lex(bytes.NewBufferString("{unquoted: 1}")); err == nil {
+		t.Error("Expected identifiers to be invalid without JSON5")
+	}
+}
+func TestJSON5CommentLineTerminators(t *testing.T) {
+
+	for _, terminator := range []string{"\n", "\r", "\r\n", "\u2028", "\u2029"} {
+		input := "[1, // comment" + terminator + "2]"
+		value, err := Options{JSON5: true}.ParseReader(strings.NewReader(input))
+		if err != nil || len(value.Elements) != 2 {
+			t.Errorf("Expected the comment to end at %q, Got : %v %v", terminator, value, err)
+		}
+	}
+	// U+2028 and U+2029 only end lines in JSON5
+	if err := (Options{JSONC: true}).Validate(strings.NewReader("[1, // comment\u20282]")); err == nil {
+		t.Error("Expected invalid but got valid")
+	}
+}
+func TestInvalidJSON5Tokens(t *testing.T) {
+
+	inputs := map[string]string{
+		"'one\ntwo'":   "1:5: Error Lexing JSON. Unescaped control character U+000A in string",
+		"'\\1'":        "1:2: Error Lexing JSON. Invalid escape sequence \\1",
+		"'\\x4'":       "1:2: Error Lexing JSON. Invalid hex escape \\x4",
+		"0xZ":          "1:3: Error Lexing JSON. Expected a hex digit after 0x",
+		"+Infinite":    "1:1: Error Lexing JSON. Invalid number +Infinite",
+		"{a\\x: 1}":    "1:3: Error Lexing JSON. Expected a \\u escape in identifier",
+		"{\\u0031: 1}": "1:2: Error Lexing JSON. Unexpected character '1' in identifier",
+		"'unclosed":    "1:1: Error Lexing JSON. Unterminated string",
+	}
+	for input, expected := range inputs {
+		_, err := Options{JSON5: true}.Lex(bytes.NewBufferString(input))
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %s, Got : %v", expected, input, err)
+		}
+	}
+}
+func TestNumberGrammar(t *testing.T) {
+
+	valid := []string{"0", "-0", "12", "-12", "0.5", "-0.5", "1e5", "1E+5", "1e-05", "10.25e3", "-0e0"}
+	for _, input := range valid {
+		tokens, err := Lex(bytes.NewBufferString(input))
+		if err != nil || tokens[0].Kind != NUMBER || tokens[0].Text != input {
+			t.Errorf("Expected %s to be a number, Got : %v %v", input, tokens, err)
+		}
+	}
+	invalid := map[string]string{
+		"01":      "1:2: Error Lexing JSON. Leading zeros are not allowed in numbers",
+		"-012":    "1:3: Error Lexing JSON. Leading zeros are not allowed in numbers",
+		"-":       "1:2: Error Lexing JSON. Expected a digit after the sign",
+		"-x":      "1:2: Error Lexing JSON. Expected a digit after the sign",
+		"-.5":     "1:2: Error Lexing JSON. Expected a digit after the sign",
+		"1.":      "1:3: Error Lexing JSON. Expected a digit after the decimal point",
+		"1.e5":    "1:3: Error Lexing JSON. Expected a digit after the decimal point",
+		"1e":      "1:3: Error Lexing JSON. Expected a digit in the exponent",
+		"1e+":     "1:4: Error Lexing JSON. Expected a digit in the exponent",
+		"1.5.3":   "1:4: Error Lexing JSON. Unexpected '.' in number",
+		"1_000":   "1:2: Error Lexing JSON. Unexpected '_' in number",
+		"0x1F":    "1:2: Error Lexing JSON. Unexpected 'x' in number",
+		"12abc":   "1:3: Error Lexing JSON. Unexpected 'a' in number",
+		"1e5e3":   "1:4: Error Lexing JSON. Unexpected 'e' in number",
+		"-Inf":    "1:2: Error Lexing JSON. Expected a digit after the sign",
+		"Inf":     "1:1: Error Lexing JSON. Unexpected Inf",
+		"NaN":     "1:1: Error Lexing JSON. Unexpected NaN",
+		".5":      "1:1: Error Lexing JSON. Unexpected character \".\"",
+		"+1":      "1:1: Error Lexing JSON. Unexpected character \"+\"",
+		"[01, 2]": "1:3: Error Lexing JSON. Leading zeros are not allowed in numbers",
+	}
+	for input, expected := range invalid {
+		_, err := Lex(bytes.NewBufferString(input))
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %s, Got : %v", expected, input, err)
+		}
+	}
+}
+func TestJSON5NumberGrammar(t *testing.T) {
+
+	for _, input := range []string{"+1", ".5", "-.5", "5.", "5.e3", "0x1F", "-0XaB", "+Infinity", "NaN"} {
+		tokens, err := Options{JSON5: true}.Lex(bytes.NewBufferString(input))
+		if err != nil || tokens[0].Kind != NUMBER || tokens[0].Text != input {
+			t.Errorf("Expected %s to be a number, Got : %v %v", input, tokens, err)
+		}
+	}
+	invalid := map[string]string{
+		"01":   "1:2: Error Lexing JSON. Leading zeros are not allowed in numbers",
+		".e3":  "1:2: Error Lexing JSON. Expected a digit after the decimal point",
+		"0x":   "1:3: Error Lexing JSON. Expected a hex digit after 0x",
+		"0x1G": "1:4: Error Lexing JSON. Unexpected 'G' in number",
+		"+-1":  "1:2: Error Lexing JSON. Expected a digit after the sign",
+	}
+	for input, expected := range invalid {
+		_, err := Options{JSON5: true}.Lex(bytes.NewBufferString(input))
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q for %s, Got : %v", expected, input, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package jsonparser
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Writes the tokens it hands out to w exactly as they appeared in the input
+type teeTokens struct {
+	tokens TokenReader
+	w      *bufio.Writer
+}
+
+func (t *teeTokens) Next() (Token, error) {
+	token, err := t.tokens.Next()
+	if err != nil {
+		return token, err
+	}
+	if _, err := t.w.WriteString(token.Text); err != nil {
+		return token, err
+	}
+	// a // comment runs to the end of the line, so it needs its line break to not swallow what follows
+	if token.Kind == COMMENT && strings.HasPrefix(token.Text, "//") {
+		if err := t.w.WriteByte('\n'); err != nil {
+			return token, err
+		}
+	}
+	return token, nil
+}
+
+// Copies the JSON in r to w with the default options, leaving out all whitespace between tokens
+func Minify(w io.Writer, r io.Reader) error {
+	return Options{}.Minify(w, r)
+}
+
+// Copies the JSON in r to w leaving out all whitespace between tokens. Strings and numbers are
+// written exactly as they appear in the input. The input is validated as it is copied, so
+// memory use doesn't grow with the size of the input, but w may have been partly written to
+// by the time an error is found
+func (o Options) Minify(w io.Writer, r io.Reader) error {
+	buffered := bufio.NewWriter(w)
+	if _, err := o.parse(&teeTokens{o.NewLexer(r), buffered}, false); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
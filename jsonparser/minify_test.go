@@ -0,0 +1,51 @@
+package jsonparser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMinify(t *testing.T) {
+
+	input := `{
+    "key with spaces": "value with\ttab escape and \"quotes\" ",
+    "numbers": [1.50, -0.0, 1E+02, 10e-1],
+    "nested": { "empty": [ ], "object": { } },
+    "keywords": [ true , false , null ]
+  }`
+	var out bytes.Buffer
+	if err := Minify(&out, strings.NewReader(input)); err != nil {
+		t.Fatalf("Expected valid but got invalid: %s", err)
+	}
+	expected := `{"key with spaces":"value with\ttab escape and \"quotes\" ","numbers":[1.50,-0.0,1E+02,10e-1],` +
+		`"nested":{"empty":[],"object":{}},"keywords":[true,false,null]}`
+	if out.String() != expected {
+		t.Errorf("Expected %s, Got : %s", expected, out.String())
+	}
+}
+func TestMinifyInvalid(t *testing.T) {
+
+	var out bytes.Buffer
+	err := Minify(&out, strings.NewReader(`{"key": [1, 2,]}`))
+	if err == nil || !strings.HasPrefix(err.Error(), "1:15: ") {
+		t.Errorf("Expected error at 1:15, Got : %v", err)
+	}
+}
+func TestMinifyComments(t *testing.T) {
+
+	input := "{\n  // the theme\n  \"theme\": \"dark\", /* inline */ \"size\": 12\n}"
+	tests := map[Options]string{
+		{JSONC: true}:                     `{"theme":"dark","size":12}`,
+		{JSONC: true, KeepComments: true}: "{// the theme\n\"theme\":\"dark\",/* inline */\"size\":12}",
+	}
+	for options, expected := range tests {
+		var out bytes.Buffer
+		if err := options.Minify(&out, strings.NewReader(input)); err != nil {
+			t.Fatalf("Expected valid but got invalid: %s", err)
+		}
+		if out.String() != expected {
+			t.Errorf("Expected %q, Got : %q", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package jsonparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// A number exactly as it was written in the input, so that 64-bit IDs, amounts of money and
+// numbers with very large exponents aren't rounded on their way through a float64.
+// The conversions report any number they can't return exactly instead of rounding it
+type Number string
+
+var (
+	// The number is too large, or too small, for the type it's converted to
+	ErrOverflow = errors.New("number overflows")
+	// The type the number is converted to can't hold it exactly, like 1.5 as an int64
+	ErrPrecisionLoss = errors.New("number loses precision")
+)
+
+// Returned by the Number conversions. Err is ErrOverflow, ErrPrecisionLoss or
+// strconv.ErrSyntax for text that isn't a number
+type NumberError struct {
+	Number Number
+	Type   string
+	Err    error
+}
+
+func (e *NumberError) Error() string {
+	switch e.Err {
+	case ErrOverflow:
+		return fmt.Sprintf("Error Converting Number. %s overflows %s", e.Number, e.Type)
+	case ErrPrecisionLoss:
+		return fmt.Sprintf("Error Converting Number. %s can't be held exactly by %s", e.Number, e.Type)
+	}
+	return fmt.Sprintf("Error Converting Number. %q is not a number", string(e.Number))
+}
+func (e *NumberError) Unwrap() error {
+	return e.Err
+}
+
+// BigInt and Rat give up with ErrOverflow rather than build a number with more digits than
+// this, so that 1e999999999 can't use up all the memory
+const maxExactDigits = 100000
+
+// exponents past this are all the same as far as any conversion is concerned
+const maxExponent = 1 << 30
+
+// A number broken down into digits × 10^exp, with no leading or trailing zeros in digits.
+// digits is empty for zero. special is set instead for JSON5's Infinity and NaN
+type decimal struct {
+	neg     bool
+	digits  string
+	exp     int
+	special string
+}
+
+// Breaks n down into its digits and exponent. Besides JSON numbers this accepts the JSON5
+// ones the lexer lets through in JSON5 mode
+func (n Number) decimal() (decimal, error) {
+	d := decimal{}
+	text := string(n)
+	if text != "" && (text[0] == '+' || text[0] == '-') {
+		d.neg, text = text[0] == '-', text[1:]
+	}
+	if text == "Infinity" || text == "NaN" {
+		d.special = text
+		return d, nil
+	}
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		hex, ok := new(big.Int).SetString(text[2:], 16)
+		if !ok || hex.Sign() < 0 {
+			return d, n.error("number", strconv.ErrSyntax)
+		}
+		text = hex.String()
+	}
+	mantissa, exponent := text, ""
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		mantissa, exponent = text[:i], text[i+1:]
+		exp, err := strconv.Atoi(exponent)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return d, n.error("number", strconv.ErrSyntax)
+		}
+		d.exp = max(-maxExponent, min(exp, maxExponent))
+	}
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	if whole+fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return d, n.error("number", strconv.ErrSyntax)
+	}
+	digits := strings.TrimLeft(whole+fraction, "0")
+	d.digits = strings.TrimRight(digits, "0")
+	d.exp += len(digits) - len(d.digits) - len(fraction)
+	if d.digits == "" {
+		d.neg, d.exp = false, 0
+	}
+	return d, nil
+}
+
+// Returns d in scientific notation, which strconv and math/big can parse whatever the exponent
+func (d decimal) String() string {
+	sign := ""
+	if d.neg {
+		sign = "-"
+	}
+	if d.digits == "" {
+		return "0"
+	}
+	return fmt.Sprintf("%s%se%d", sign, d.digits, d.exp)
+}
+
+// Checks that n is a whole number of at most maxDigits digits, for converting to typ
+func (n Number) integer(typ string, maxDigits int) (decimal, error) {
+	d, err := n.decimal()
+	switch {
+	case err != nil:
+		return d, err
+	case d.special != "" || len(d.digits)+d.exp > maxDigits:
+		return d, n.error(typ, ErrOverflow)
+	case d.exp < 0:
+		return d, n.error(typ, ErrPrecisionLoss)
+	}
+	return d, nil
+}
+
+// Returns n as an int64
+func (n Number) Int64() (int64, error) {
+	d, err := n.integer("int64", 19)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseInt(d.digitsWithSign(), 10, 64)
+	if err != nil {
+		return 0, n.error("int64", ErrOverflow)
+	}
+	return i, nil
+}
+
+// Returns n as a uint64. Negative numbers overflow
+func (n Number) Uint64() (uint64, error) {
+	d, err := n.integer("uint64", 20)
+	if err != nil {
+		return 0, err
+	}
+	if d.neg {
+		return 0, n.error("uint64", ErrOverflow)
+	}
+	u, err := strconv.ParseUint(d.digitsWithSign(), 10, 64)
+	if err != nil {
+		return 0, n.error("uint64", ErrOverflow)
+	}
+	return u, nil
+}
+
+// Returns n as an integer of any size
+func (n Number) BigInt() (*big.Int, error) {
+	d, err := n.integer("*big.Int", maxExactDigits)
+	if err != nil {
+		return nil, err
+	}
+	i, _ := new(big.Int).SetString(d.digitsWithSign(), 10)
+	return i, nil
+}
+
+// Returns n as an exact fraction
+func (n Number) Rat() (*big.Rat, error) {
+	d, err := n.decimal()
+	switch {
+	case err != nil:
+		return nil, err
+	case d.special != "" || len(d.digits)+abs(d.exp) > maxExactDigits:
+		return nil, n.error("*big.Rat", ErrOverflow)
+	}
+	r, _ := new(big.Rat).SetString(d.String())
+	return r, nil
+}
+
+// Returns the float64 nearest to n. Numbers with more significant digits than a float64 holds,
+// like 0.10000000000000000001 or 9007199254740993, report ErrPrecisionLoss along with the nearest
+// value, but 0.1 doesn't, since it reads back as the same number. Infinity and NaN are converted
+func (n Number) Float64() (float64, error) {
+	d, err := n.decimal()
+	if err != nil {
+		return 0, err
+	}
+	switch d.special {
+	case "Infinity":
+		if d.neg {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	f, err := strconv.ParseFloat(d.String(), 64)
+	if err != nil {
+		return f, n.error("float64", ErrOverflow)
+	}
+	if !d.equal(Number(strconv.FormatFloat(f, 'e', -1, 64))) {
+		return f, n.error("float64", ErrPrecisionLoss)
+	}
+	return f, nil
+}
+
+// Returns n as a big.Float with enough precision to hold every digit. Only numbers that are
+// out of big.Float's enormous range overflow, or lose precision when they are too close to 0.
+// Infinity is converted but NaN overflows, since big.Float has no NaN
+func (n Number) BigFloat() (*big.Float, error) {
+	d, err := n.decimal()
+	if err != nil {
+		return nil, err
+	}
+	switch d.special {
+	case "Infinity":
+		return new(big.Float).SetInf(d.neg), nil
+	case "NaN":
+		return nil, n.error("*big.Float", ErrOverflow)
+	}
+	// a decimal digit needs a little under 4 bits
+	prec := uint(4*len(d.digits) + 64)
+	f, _, err := big.ParseFloat(d.String(), 10, prec, big.ToNearestEven)
+	if err != nil || f.IsInf() {
+		return nil, n.error("*big.Float", ErrOverflow)
+	}
+	if !d.equal(Number(f.Text('e', len(d.digits)))) {
+		return f, n.error("*big.Float", ErrPrecisionLoss)
+	}
+	return f, nil
+}
+
+// Returns the digits of a whole number with its sign and the trailing zeros written out
+func (d decimal) digitsWithSign() string {
+	if d.digits == "" {
+		return "0"
+	}
+	sign := ""
+	if d.neg {
+		sign = "-"
+	}
+	return sign + d.digits + strings.Repeat("0", d.exp)
+}
+
+// Reports whether d and the number in text have the same value
+func (d decimal) equal(text Number) bool {
+	other, err := text.decimal()
+	return err == nil && d == other
+}
+
+// Returns n spelled the way JSON allows: a JSON5 + sign is dropped, hex becomes decimal and
+// a leading or trailing decimal point gets its zero back or is dropped. JSON numbers are
+// returned as they are. ok is false for Infinity, NaN and text that isn't a number
+func (n Number) jsonText() (text string, ok bool) {
+	d, err := n.decimal()
+	if err != nil || d.special != "" {
+		return "", false
+	}
+	sign, text := "", strings.TrimPrefix(string(n), "+")
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		return d.digitsWithSign(), true
+	}
+	if strings.HasPrefix(text, ".") {
+		text = "0" + text
+	}
+	if point := strings.IndexByte(text, '.'); point >= 0 && (point == len(text)-1 || !isDigit(rune(text[point+1]))) {
+		text = text[:point] + text[point+1:]
+	}
+	return sign + text, true
+}
+
+func (n Number) error(typ string, err error) error {
+	return &NumberError{Number: n, Type: typ, Err: err}
+}
+
+func isDigits(s string) bool {
+	for _, char := range s {
+		if !isDigit(char) {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,163 @@
+package jsonparser
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNumberInt64(t *testing.T) {
+
+	tests := []struct {
+		number   Number
+		expected int64
+		err      error
+	}{
+		{"0", 0, nil},
+		{"-0", 0, nil},
+		{"9223372036854775807", math.MaxInt64, nil},
+		{"-9223372036854775808", math.MinInt64, nil},
+		{"9223372036854775808", 0, ErrOverflow},
+		{"1e18", 1e18, nil},
+		{"1e19", 0, ErrOverflow},
+		{"1.5e3", 1500, nil},
+		{"100e-2", 1, nil},
+		{"1.5", 0, ErrPrecisionLoss},
+		{"1e-400", 0, ErrPrecisionLoss},
+		{"0e999999999999", 0, nil},
+		{"0x7FFFFFFFFFFFFFFF", math.MaxInt64, nil},
+		{"Infinity", 0, ErrOverflow},
+		{"abc", 0, strconv.ErrSyntax},
+	}
+	for _, test := range tests {
+		i, err := test.number.Int64()
+		if !errors.Is(err, test.err) || (err == nil && test.err != nil) || i != test.expected {
+			t.Errorf("%s: Expected %d %v, Got : %d %v", test.number, test.expected, test.err, i, err)
+		}
+	}
+}
+func TestNumberUint64(t *testing.T) {
+
+	tests := []struct {
+		number   Number
+		expected uint64
+		err      error
+	}{
+		{"18446744073709551615", math.MaxUint64, nil},
+		{"18446744073709551616", 0, ErrOverflow},
+		{"-1", 0, ErrOverflow},
+		{"-0", 0, nil},
+		{"2.5", 0, ErrPrecisionLoss},
+	}
+	for _, test := range tests {
+		u, err := test.number.Uint64()
+		if !errors.Is(err, test.err) || (err == nil && test.err != nil) || u != test.expected {
+			t.Errorf("%s: Expected %d %v, Got : %d %v", test.number, test.expected, test.err, u, err)
+		}
+	}
+}
+func TestNumberFloat64(t *testing.T) {
+
+	tests := []struct {
+		number   Number
+		expected float64
+		err      error
+	}{
+		{"0.1", 0.1, nil},
+		{"-2.5e-3", -2.5e-3, nil},
+		{"9007199254740992", 9007199254740992, nil},
+		{"9007199254740993", 9007199254740992, ErrPrecisionLoss},
+		{"0.10000000000000000001", 0.1, ErrPrecisionLoss},
+		{"1e400", math.Inf(1), ErrOverflow},
+		{"1e-400", 0, ErrPrecisionLoss},
+		{"-Infinity", math.Inf(-1), nil},
+	}
+	for _, test := range tests {
+		f, err := test.number.Float64()
+		if !errors.Is(err, test.err) || (err == nil && test.err != nil) || f != test.expected {
+			t.Errorf("%s: Expected %v %v, Got : %v %v", test.number, test.expected, test.err, f, err)
+		}
+	}
+	if f, err := Number("NaN").Float64(); err != nil || !math.IsNaN(f) {
+		t.Errorf("Expected NaN, Got : %v %v", f, err)
+	}
+}
+func TestNumberBig(t *testing.T) {
+
+	i, err := Number("123456789012345678901234567890e5").BigInt()
+	if err != nil || i.String() != "12345678901234567890123456789000000" {
+		t.Errorf("Expected 12345678901234567890123456789000000, Got : %v %v", i, err)
+	}
+	if _, err := Number("1.5").BigInt(); !errors.Is(err, ErrPrecisionLoss) {
+		t.Errorf("Expected %v, Got : %v", ErrPrecisionLoss, err)
+	}
+	if _, err := Number("1e999999999").BigInt(); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected %v, Got : %v", ErrOverflow, err)
+	}
+
+	r, err := Number("-19.99").Rat()
+	if err != nil || r.Cmp(big.NewRat(-1999, 100)) != 0 {
+		t.Errorf("Expected -1999/100, Got : %v %v", r, err)
+	}
+	if _, err := Number("1e-999999999").Rat(); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected %v, Got : %v", ErrOverflow, err)
+	}
+
+	f, err := Number("1e400").BigFloat()
+	if err != nil || f.Text('g', 5) != "1e+400" {
+		t.Errorf("Expected 1e+400, Got : %v %v", f, err)
+	}
+	digits := "3." + strings.Repeat("14159", 20)
+	f, err = Number(digits).BigFloat()
+	if err != nil || f.Text('f', 100) != digits {
+		t.Errorf("Expected %s, Got : %v %v", digits, f, err)
+	}
+	if _, err := Number("NaN").BigFloat(); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected %v, Got : %v", ErrOverflow, err)
+	}
+}
+func TestNumberError(t *testing.T) {
+
+	_, err := Number("1.5").Int64()
+	expected := "Error Converting Number. 1.5 can't be held exactly by int64"
+	var numberErr *NumberError
+	if !errors.As(err, &numberErr) || err.Error() != expected {
+		t.Errorf("Expected %q, Got : %v", expected, err)
+	}
+	if _, err := Number("1e30").Uint64(); err == nil || err.Error() != "Error Converting Number. 1e30 overflows uint64" {
+		t.Errorf("Expected an overflow error, Got : %v", err)
+	}
+}
+func TestNumberPreserved(t *testing.T) {
+
+	input := `{"id": 12345678901234567890, "price": 19.990, "big": 1e999, "tiny": -0.0}`
+	value, err := ParseReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected valid but got invalid: %s", err)
+	}
+	id, _ := value.Get("id")
+	if u, err := id.Number.Uint64(); err != nil || u != 12345678901234567890 {
+		t.Errorf("Expected 12345678901234567890, Got : %d %v", u, err)
+	}
+	if huge, _ := value.Get("big"); !math.IsInf(huge.Num, 1) || huge.Number != "1e999" {
+		t.Errorf("Expected +Inf and 1e999, Got : %v %s", huge.Num, huge.Number)
+	}
+	data, _ := Marshal(value)
+	if expected := `{"id":12345678901234567890,"price":19.990,"big":1e999,"tiny":-0.0}`; string(data) != expected {
+		t.Errorf("Expected %s, Got : %s", expected, data)
+	}
+
+	var decoded struct {
+		ID    uint64
+		Price Number
+	}
+	if err := value.Decode(&decoded); err != nil || decoded.ID != 12345678901234567890 || decoded.Price != "19.990" {
+		t.Errorf("Expected the exact id and price, Got : %+v %v", decoded, err)
+	}
+	if data, _ := Marshal(decoded); string(data) != `{"ID":12345678901234567890,"Price":19.990}` {
+		t.Errorf("Expected the price to be written as a number, Got : %s", data)
+	}
+}
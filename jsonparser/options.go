@@ -0,0 +1,123 @@
+package jsonparser
+
+import "fmt"
+
+// Options that change what the parser accepts. The zero value follows RFC 8259
+type Options struct {
+	// Only accept an object or array at the top level like RFC 4627 did
+	RFC4627 bool
+	// Allow // and /* */ comments outside strings, as in VS Code settings and tsconfig files
+	JSONC bool
+	// Accept JSON5: comments, unquoted identifier keys, single quoted and multi-line strings,
+	// trailing commas, hex numbers, leading and trailing decimal points, + signs, Infinity and NaN
+	JSON5 bool
+	// Return comments from the lexer as COMMENT tokens instead of skipping them. The parser
+	// always skips them. Only used with JSONC or JSON5
+	KeepComments bool
+	// What to do with an object key that appears more than once in the same object
+	DuplicateKeys DuplicateKeyPolicy
+	// Problems that don't make the input invalid, like duplicate keys with WarnDuplicateKeys,
+	// are added to the list it points to when it's set
+	Warnings *ErrorList
+	// How deeply objects and arrays can be nested. 0 uses DefaultMaxDepth and a negative value
+	// removes the limit, which is only safe for untrusted input along with Iterative
+	MaxDepth int
+	// Parse with a stack of open objects and arrays on the heap instead of recursion, so deeply
+	// nested input is limited by MaxDepth and memory rather than the goroutine stack. The
+	// iterative parser stops at the first error, whatever MaxErrors says
+	Iterative bool
+	// Limits on untrusted input. Going past one stops parsing with an error whose code says
+	// which, like InputTooLarge, whatever MaxErrors says. 0 means no limit.
+	//
+	// The most bytes of input that are read. Reading stops at the limit, so the rest of a larger
+	// input is never read
+	MaxInputBytes int64
+	// The most tokens in the input, counting punctuation. Comments only count when Lex returns
+	// them with KeepComments
+	MaxTokens int
+	// The longest a string or JSON5 identifier can be, in bytes as written in the input with any
+	// escapes but not the quotes
+	MaxStringLength int
+	// The longest a number can be, in characters
+	MaxNumberLength int
+	// The most members an object can have
+	MaxMembers int
+	// Carry on parsing after a syntax error to find more of them, up to MaxErrors, and return
+	// them together as an ErrorList. 0 or 1 stops at the first error
+	MaxErrors int
+}
+
+// The nesting limit used when Options.MaxDepth is 0. The recursive parser stays well within
+// the goroutine stack at this depth
+const DefaultMaxDepth = 1000
+
+// Returns the nesting limit, or -1 for none
+func (o Options) maxDepth() int {
+	switch {
+	case o.MaxDepth == 0:
+		return DefaultMaxDepth
+	case o.MaxDepth < 0:
+		return -1
+	}
+	return o.MaxDepth
+}
+
+// How the parser treats an object key that appears more than once in the same object,
+// which RFC 8259 leaves up to the parser
+type DuplicateKeyPolicy int
+
+const (
+	// Keep every member, duplicates included. Value.Get finds the first of them
+	AllowDuplicateKeys DuplicateKeyPolicy = iota
+	// Report a DuplicateKey syntax error at the second key, which gives where the first one is
+	RejectDuplicateKeys
+	// Keep every member like AllowDuplicateKeys, and add a DuplicateKey warning to Options.Warnings
+	WarnDuplicateKeys
+	// Keep the first member with the key and drop the rest
+	KeepFirstDuplicateKey
+	// Keep the last value for the key, in the place of the first member with it like JavaScript does
+	KeepLastDuplicateKey
+)
+
+var duplicateKeyPolicyNames = [...]string{
+	AllowDuplicateKeys:    "allow",
+	RejectDuplicateKeys:   "error",
+	WarnDuplicateKeys:     "warn",
+	KeepFirstDuplicateKey: "keep-first",
+	KeepLastDuplicateKey:  "keep-last",
+}
+
+func (d DuplicateKeyPolicy) String() string {
+	if d < 0 || int(d) >= len(duplicateKeyPolicyNames) {
+		return fmt.Sprintf("DuplicateKeyPolicy(%d)", int(d))
+	}
+	return duplicateKeyPolicyNames[d]
+}
+
+// Returns the policy named name, one of allow, error, warn, keep-first or keep-last
+func ParseDuplicateKeyPolicy(name string) (DuplicateKeyPolicy, error) {
+	for policy, policyName := range duplicateKeyPolicyNames {
+		if name == policyName {
+			return DuplicateKeyPolicy(policy), nil
+		}
+	}
+	return 0, fmt.Errorf("Unknown duplicate key policy %q, expected allow, error, warn, keep-first or keep-last", name)
+}
+
+// Options that change how values are written out by Marshal. The zero value writes compact JSON
+type EncodeOptions struct {
+	// Put each array element and object member on its own line, indented by Indent for every level of nesting
+	Indent string
+	// Escape <, > and & so the output can be embedded in HTML
+	EscapeHTML bool
+	// Write the members of a parsed object sorted by key instead of in input order. Maps are always sorted
+	SortKeys bool
+}
+
+// Options for SyntaxError.Render
+type RenderOptions struct {
+	// Number of lines of input to show before and after the line with the error
+	Context int
+	// Highlight the output with ANSI escape codes for a terminal
+	Color bool
+}
@@ -0,0 +1,573 @@
+// Package jsonparser lexes, parses and validates JSON as described by RFC 8259.
+//
+// Parse, ParseBuffer and ParseReader build a Value tree from the input, while Validate only
+// checks the input and never holds more of it in memory than the current nesting requires.
+// The package level functions use the default Options; call the Options methods to change them
+package jsonparser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Anything the parser can pull tokens from one at a time, such as a Lexer
+type TokenReader interface {
+	Next() (Token, error)
+}
+
+// Serves tokens that have already been lexed
+type tokenSlice struct {
+	tokens []Token
+	pos    int
+}
+
+func (s *tokenSlice) Next() (Token, error) {
+	token := tokenAt(&s.tokens, s.pos)
+	s.pos++
+	return token, nil
+}
+
+// Recursive descent parser that only looks one token ahead,
+// so the input is never held in memory all at once
+type parser struct {
+	tokens  TokenReader
+	token   Token // the next token, not consumed yet
+	prev    Token // the token consumed last
+	options Options
+	// validating doesn't need the tree so members and elements are dropped as soon as they're parsed
+	build bool
+	// syntax errors found so far when Options.MaxErrors allows more than one
+	errors ErrorList
+	// set while resynchronizing after an error, until the next comma or colon
+	recovering bool
+	// number of objects and arrays open around the current token
+	depth int
+	// number of tokens read so far, for Options.MaxTokens
+	count int
+}
+
+// Parses tokens with the default options
+func Parse(tokens *[]Token) (*Value, error) {
+	return Options{}.Parse(tokens)
+}
+func (o Options) Parse(tokens *[]Token) (*Value, error) {
+	return o.parse(&tokenSlice{tokens: *tokens}, true)
+}
+
+// Lexes and parses a buffer of JSON with the default options
+func ParseBuffer(buf *bytes.Buffer) (*Value, error) {
+	return Options{}.ParseBuffer(buf)
+}
+func (o Options) ParseBuffer(buf *bytes.Buffer) (*Value, error) {
+	return o.ParseReader(buf)
+}
+
+// Lexes and parses JSON from r as it is read, with the default options
+func ParseReader(r io.Reader) (*Value, error) {
+	return Options{}.ParseReader(r)
+}
+func (o Options) ParseReader(r io.Reader) (*Value, error) {
+	return o.parse(o.NewLexer(r), true)
+}
+
+// Checks that r holds valid JSON with the default options. No tree is built,
+// so memory use grows with how deeply the input is nested rather than its size
+func Validate(r io.Reader) error {
+	return Options{}.Validate(r)
+}
+func (o Options) Validate(r io.Reader) error {
+	_, err := o.parse(o.NewLexer(r), false)
+	return err
+}
+
+func (o Options) parse(tokens TokenReader, build bool) (*Value, error) {
+
+	// in recursive descent parsers we write a method to match each "entity " in the string
+	// we also have methods that implement a production rule in the grammar, so basically we need function to match:
+	// keyword tokens, numbers, strings, objects, and arrays
+	p := &parser{tokens: tokens, options: o, build: build}
+	if o.Iterative {
+		// the iterative parser doesn't recover from errors
+		p.options.MaxErrors = 0
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if o.RFC4627 && p.token.Kind != LEFTCURLYBRACE && p.token.Kind != LEFTSQUAREBRACE {
+		if err := p.fail(parserError(p.token, ScalarTopLevel, "{ or [")); err != nil {
+			return nil, err
+		}
+	}
+	var value *Value
+	var err error
+	if o.Iterative {
+		value, err = p.parseIterative()
+	} else {
+		value, err = p.parseValues()
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the top level value has to be the only thing in the input
+	if p.token.Kind != EOF {
+		if err := p.fail(parserError(p.token, TrailingData, EOF.String())); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.errors) > 0 {
+		return nil, p.errors
+	}
+	return value, nil
+}
+func (p *parser) parseObject() (*Value, error) {
+
+	object := &Value{Kind: ObjectValue, Members: []Member{}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.token.Kind == RIGHTCURLYBRACE {
+		return object, nil
+	}
+	// where each key was first seen, when duplicates need to be found
+	var seen map[string]firstKey
+	if p.options.DuplicateKeys != AllowDuplicateKeys {
+		seen = map[string]firstKey{}
+	}
+	members := 0
+	for {
+		key, ok := p.token, p.isKey(p.token)
+		if !ok {
+			code := UnexpectedToken
+			if p.token.Kind == RIGHTCURLYBRACE {
+				// only reachable after a comma
+				if p.options.JSON5 {
+					return object, nil
+				}
+				code = TrailingComma
+			}
+			if err := p.fail(parserError(p.token, code, p.keyExpected())); err != nil {
+				return nil, err
+			}
+			if code == TrailingComma {
+				return object, nil
+			}
+			// the member is dropped, but its value is still parsed if a colon turns up
+			stop, err := p.skip(RIGHTCURLYBRACE, true)
+			if err != nil || (stop != COMMA && stop != COLON) {
+				return object, err
+			}
+			if stop == COMMA {
+				continue
+			}
+		} else {
+			if err := p.countMember(&members); err != nil {
+				return nil, err
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			if p.token.Kind == COLON {
+				p.recovering = false
+				if err := p.next(); err != nil {
+					return nil, err
+				}
+			} else {
+				if err := p.fail(parserError(p.token, MissingColon, COLON.String())); err != nil {
+					return nil, err
+				}
+				// carry on as if the colon was there when a value follows, otherwise give up on the member
+				if !startsValue(p.token.Kind) {
+					stop, err := p.skip(RIGHTCURLYBRACE, false)
+					if err != nil || stop != COMMA {
+						return object, err
+					}
+					continue
+				}
+			}
+		}
+		value, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if err := p.addMember(object, seen, key, value); err != nil {
+				return nil, err
+			}
+		}
+		done, err := p.parseValueEnding(RIGHTCURLYBRACE)
+		if err != nil || done {
+			return object, err
+		}
+	}
+
+}
+
+// Where a key first appeared in an object and the index of its member
+type firstKey struct {
+	pos   Position
+	index int
+}
+
+// Adds the member for key to object, unless Options.DuplicateKeys says otherwise for a key
+// that is already in seen
+func (p *parser) addMember(object *Value, seen map[string]firstKey, key Token, value *Value) error {
+	first, duplicate := seen[key.Value]
+	if !duplicate {
+		if seen != nil {
+			seen[key.Value] = firstKey{key.Pos, len(object.Members)}
+		}
+		if p.build {
+			object.Members = append(object.Members, Member{key.Value, value})
+		}
+		return nil
+	}
+	err := &SyntaxError{
+		Code:     DuplicateKey,
+		Actual:   key.Text,
+		Pos:      key.Pos,
+		End:      key.End,
+		Msg:      fmt.Sprintf("Duplicate key %s, first defined at %s", key.Text, first.pos),
+		Original: first.pos,
+	}
+	switch p.options.DuplicateKeys {
+	case RejectDuplicateKeys:
+		return p.fail(err)
+	case WarnDuplicateKeys:
+		if p.options.Warnings != nil {
+			err.Warning = true
+			*p.options.Warnings = append(*p.options.Warnings, err)
+		}
+		if p.build {
+			object.Members = append(object.Members, Member{key.Value, value})
+		}
+	case KeepLastDuplicateKey:
+		if p.build {
+			object.Members[first.index].Value = value
+		}
+	}
+	return nil
+}
+
+// Parses the token after an element of an array or a member of an object, which should be a
+// comma or the closing bracket. The comma is consumed so the next element is up next.
+//
+// Returns: bool specifying whether to return from calling function and Error value
+func (p *parser) parseValueEnding(closing TokenKind) (bool, error) {
+
+	switch p.token.Kind {
+	case closing:
+		return true, nil // at this point we want to stop parsing the object or array
+	case COMMA:
+		p.recovering = false
+		return false, p.next()
+	}
+	code := UnexpectedToken
+	if startsValue(p.token.Kind) || (closing == RIGHTCURLYBRACE && p.isKey(p.token)) {
+		code = MissingComma
+	}
+	if err := p.fail(parserError(p.token, code, closing.String())); err != nil {
+		return false, err
+	}
+	if code == MissingComma {
+		// carry on as if the comma was there
+		return false, nil
+	}
+	stop, err := p.skip(closing, false)
+	return stop != COMMA, err
+}
+func (p *parser) parseArray() (*Value, error) {
+
+	array := &Value{Kind: ArrayValue, Elements: []*Value{}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.token.Kind == RIGHTSQUAREBRACE {
+		return array, nil
+	}
+	for {
+		// only reachable after a comma
+		if p.token.Kind == RIGHTSQUAREBRACE {
+			if p.options.JSON5 {
+				return array, nil
+			}
+			if err := p.fail(parserError(p.token, TrailingComma, "value")); err != nil {
+				return nil, err
+			}
+			return array, nil
+		}
+		value, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		if p.build {
+			array.Elements = append(array.Elements, value)
+		}
+		done, err := p.parseValueEnding(RIGHTSQUAREBRACE)
+		if err != nil || done {
+			return array, err
+		}
+	}
+}
+
+// Parse out a string,object,number, or array. Leaves the token after the value up next
+func (p *parser) parseValues() (*Value, error) {
+	var value *Value
+	var err error
+	start := p.token.Pos
+	switch p.token.Kind {
+	case LEFTCURLYBRACE, LEFTSQUAREBRACE:
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		if p.token.Kind == LEFTCURLYBRACE {
+			value, err = p.parseObject()
+		} else {
+			value, err = p.parseArray()
+		}
+		p.depth--
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return p.parseScalar()
+	}
+	value.Pos = start
+	// after an error the object or array can end at the wrong kind of bracket, which is left
+	// to close the one around it
+	if p.token.Kind != closingOf(value) {
+		return value, nil
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Parse out a string, number, true, false or null. Leaves the token after the value up next
+func (p *parser) parseScalar() (*Value, error) {
+	var value *Value
+	start := p.token.Pos
+	switch token := p.token; token.Kind {
+	case STRING:
+		value = &Value{Kind: StringValue, Str: token.Value}
+	case TRUE, FALSE:
+		value = &Value{Kind: BoolValue, Bool: token.Kind == TRUE}
+	case NULL:
+		value = &Value{Kind: NullValue}
+	case NUMBER:
+		// numbers too large for a float64 are still valid, Number keeps them exactly
+		num, err := Number(token.Value).Float64()
+		if errors.Is(err, strconv.ErrSyntax) {
+			if err := p.fail(parserError(token, InvalidNumber, NUMBER.String())); err != nil {
+				return nil, err
+			}
+		}
+		value = &Value{Kind: NumberValue, Num: num, Number: Number(token.Value)}
+	default:
+		if err := p.fail(parserError(token, UnexpectedToken, "value")); err != nil {
+			return nil, err
+		}
+		// leave the token for the enclosing object or array to resynchronize at
+		p.recovering = true
+		return &Value{Kind: NullValue, Pos: start}, nil
+	}
+	value.Pos = start
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Counts the object or array starting at the current token towards Options.MaxDepth
+func (p *parser) enter() error {
+	p.depth++
+	if limit := p.options.maxDepth(); limit >= 0 && p.depth > limit {
+		return p.stop(parserLimitError(p.token, TooDeep, fmt.Sprintf("Objects and arrays are nested more than %d deep", limit)))
+	}
+	return nil
+}
+
+// Moves on to the next token. When recovering from errors, tokens the lexer can't make sense
+// of are recorded and dropped
+func (p *parser) next() error {
+	for {
+		token, err := p.tokens.Next()
+		if err == nil && token.Kind == COMMENT {
+			continue
+		}
+		if err == nil {
+			p.prev, p.token = p.token, token
+			return p.countToken()
+		}
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Code.limit() {
+			return p.stop(syntaxErr)
+		}
+		if err := p.fail(err); err != nil {
+			return err
+		}
+		p.recovering = true
+	}
+}
+
+// Counts the current token towards Options.MaxTokens
+func (p *parser) countToken() error {
+	if p.token.Kind == EOF {
+		return nil
+	}
+	p.count++
+	if limit := p.options.MaxTokens; limit > 0 && p.count > limit {
+		return p.stop(tooManyTokens(p.token, limit))
+	}
+	return nil
+}
+
+// Counts the member whose key is the current token towards Options.MaxMembers. members is
+// the number of members of the object so far
+func (p *parser) countMember(members *int) error {
+	*members++
+	if limit := p.options.MaxMembers; limit > 0 && *members > limit {
+		return p.stop(parserLimitError(p.token, TooManyMembers, fmt.Sprintf("Objects can't have more than %d members", limit)))
+	}
+	return nil
+}
+
+// Returns err to stop parsing at it even when Options.MaxErrors allows more, for errors there
+// is no carrying on from
+func (p *parser) stop(err *SyntaxError) error {
+	if p.options.MaxErrors < 2 {
+		return err
+	}
+	return append(p.errors, err)
+}
+
+// Returns err to stop parsing at it, unless Options.MaxErrors asks for more than one error.
+// Then syntax errors are recorded and nil is returned so parsing can carry on, until the limit
+// is reached and every error found is returned together. While resynchronizing after an error
+// only running out of input is recorded, since anything else is likely caused by the first error
+func (p *parser) fail(err error) error {
+	var syntaxErr *SyntaxError
+	if p.options.MaxErrors < 2 || !errors.As(err, &syntaxErr) {
+		return err
+	}
+	if p.recovering && syntaxErr.Code != UnexpectedEOF {
+		return nil
+	}
+	// an error at the end of the input is seen by every object and array left open
+	if n := len(p.errors); n > 0 && p.errors[n-1].Pos == syntaxErr.Pos {
+		return nil
+	}
+	p.errors = append(p.errors, syntaxErr)
+	if len(p.errors) >= p.options.MaxErrors {
+		return p.errors
+	}
+	return nil
+}
+
+// Skips the tokens after an error up to the next comma, or colon if colon is set, in the
+// object or array being parsed, so that parsing can carry on from there. Nested objects and
+// arrays are skipped whole. Returns the kind of token it stopped at: COMMA or COLON once it
+// has been consumed, otherwise the object or array has ended at the closing bracket or EOF
+// that is up next. Any closing bracket ends it, since a mismatched one is most likely meant to
+func (p *parser) skip(closing TokenKind, colon bool) (TokenKind, error) {
+	p.recovering = true
+	depth := 0
+	for {
+		switch p.token.Kind {
+		case EOF:
+			return EOF, p.fail(parserError(p.token, UnexpectedEOF, closing.String()))
+		case LEFTCURLYBRACE, LEFTSQUAREBRACE:
+			depth++
+		case RIGHTCURLYBRACE, RIGHTSQUAREBRACE:
+			if depth == 0 {
+				return p.token.Kind, nil
+			}
+			depth--
+		case COMMA:
+			if depth == 0 {
+				p.recovering = false
+				return COMMA, p.next()
+			}
+		case COLON:
+			if depth == 0 && colon {
+				p.recovering = false
+				return COLON, p.next()
+			}
+		}
+		if err := p.next(); err != nil {
+			return EOF, err
+		}
+	}
+}
+
+// Describes what an object key can be, for errors
+func (p *parser) keyExpected() string {
+	if p.options.JSON5 {
+		return "string or identifier"
+	}
+	return STRING.String()
+}
+
+// Reports whether token can be an object key. JSON5 also allows identifiers, including
+// true, false, null, Infinity and NaN
+func (p *parser) isKey(token Token) bool {
+	switch token.Kind {
+	case STRING:
+		return true
+	case IDENTIFIER, TRUE, FALSE, NULL:
+		return p.options.JSON5
+	case NUMBER:
+		return p.options.JSON5 && (token.Text == "Infinity" || token.Text == "NaN")
+	}
+	return false
+}
+
+// Returns the token at pos, or an EOF token if pos is past the last token
+func tokenAt(tokens *[]Token, pos int) Token {
+	if pos < len(*tokens) {
+		return (*tokens)[pos]
+	}
+	if len(*tokens) == 0 {
+		return Token{Kind: EOF, Pos: Position{Line: 1, Column: 1}}
+	}
+	end := (*tokens)[len(*tokens)-1].End
+	return Token{Kind: EOF, Pos: end, End: end}
+}
+
+// Reports whether a token of kind can start a value
+func startsValue(kind TokenKind) bool {
+	switch kind {
+	case LEFTCURLYBRACE, LEFTSQUAREBRACE, STRING, NUMBER, TRUE, FALSE, NULL:
+		return true
+	}
+	return false
+}
+
+// Returns an error for finding token where expected should be. Running out of
+// input is always reported as UnexpectedEOF rather than code
+func parserError(token Token, code ErrorCode, expected string) error {
+	if token.Kind == EOF {
+		code = UnexpectedEOF
+	}
+	return &SyntaxError{
+		Code:     code,
+		Expected: expected,
+		Actual:   token.String(),
+		Pos:      token.Pos,
+		End:      token.End,
+		Msg:      fmt.Sprintf("Expected %s but got %s", expected, token),
+	}
+}
+
+// Returns an error for input that goes past one of the limits in Options at token
+func parserLimitError(token Token, code ErrorCode, message string) *SyntaxError {
+	return &SyntaxError{Code: code, Actual: token.Text, Pos: token.Pos, End: token.End, Msg: message}
+}
+
+// Returns the error for token when it is one more than Options.MaxTokens allows
+func tooManyTokens(token Token, limit int) *SyntaxError {
+	return parserLimitError(token, TooManyTokens, fmt.Sprintf("The input has more than %d tokens", limit))
+}
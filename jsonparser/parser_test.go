@@ -0,0 +1,498 @@
+package jsonparser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type Json struct {
+	file *os.File
+	path string
+}
+
+var validFiles = []Json{}
+var invalidFiles = []Json{}
+var _ = filepath.Walk("testdata", func(path string, info os.FileInfo, walkErr error) error {
+	if walkErr != nil {
+		return walkErr
+	}
+
+	if ext := filepath.Ext(path); ext != "" && ext == ".json" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(path, "fail") || strings.Contains(path, "invalid") {
+			invalidFiles = append(invalidFiles, Json{f, path})
+		} else if strings.Contains(path, "pass") || strings.Index(filepath.Base(path), "valid") == 0 {
+			validFiles = append(validFiles, Json{f, path})
+		}
+	}
+	return nil
+})
+
+func TestInvalid(t *testing.T) {
+
+	for _, json := range invalidFiles {
+
+		buf := bytes.NewBuffer(make([]byte, 0))
+		io.Copy(buf, json.file)
+		// the JSON_checker suite predates RFC 8259 and expects a bare value at the top level to be rejected
+		options := Options{RFC4627: strings.Contains(json.path, "rfctests")}
+		_, parseErr := options.ParseBuffer(buf)
+		if parseErr == nil {
+
+			t.Errorf("Expected invalid but got valid: %s", json.path)
+		}
+	}
+}
+func TestValid(t *testing.T) {
+
+	for _, json := range validFiles {
+
+		buf := bytes.NewBuffer(make([]byte, 0))
+		io.Copy(buf, json.file)
+		_, parseErr := ParseBuffer(buf)
+		if parseErr != nil {
+
+			t.Errorf("Expected valid but got invalid for %s: %s", json.path, parseErr)
+		}
+	}
+}
+func TestValueTree(t *testing.T) {
+
+	buf := bytes.NewBufferString(`{"name": "json", "tags": ["a", "b"], "count": 2.5, "ok": true, "none": null, "nested": {"key": false}}`)
+	value, err := ParseBuffer(buf)
+	if err != nil {
+		t.Fatalf("Expected valid but got invalid: %s", err)
+	}
+	if value.Kind != ObjectValue || len(value.Members) != 6 {
+		t.Fatalf("Expected object with 6 members, Got : %d", len(value.Members))
+	}
+	keys := []string{"name", "tags", "count", "ok", "none", "nested"}
+	for i, member := range value.Members {
+		if member.Key != keys[i] {
+			t.Errorf("Expected key %s at %d, Got : %s", keys[i], i, member.Key)
+		}
+	}
+	if name, _ := value.Get("name"); name.Kind != StringValue || name.Str != "json" {
+		t.Errorf("Expected name to be json, Got : %v", name.Interface())
+	}
+	tags, _ := value.Get("tags")
+	if tag, ok := tags.Index(1); !ok || tag.Str != "b" {
+		t.Errorf("Expected second tag to be b, Got : %v", tags.Interface())
+	}
+	if count, _ := value.Get("count"); count.Kind != NumberValue || count.Num != 2.5 {
+		t.Errorf("Expected count to be 2.5, Got : %v", count.Interface())
+	}
+	if ok, _ := value.Get("ok"); ok.Kind != BoolValue || !ok.Bool {
+		t.Errorf("Expected ok to be true, Got : %v", ok.Interface())
+	}
+	if none, _ := value.Get("none"); none.Kind != NullValue {
+		t.Errorf("Expected none to be null, Got : %v", none.Interface())
+	}
+	nested, _ := value.Get("nested")
+	if key, _ := nested.Get("key"); key.Kind != BoolValue || key.Bool {
+		t.Errorf("Expected nested key to be false, Got : %v", key.Interface())
+	}
+}
+func TestValueKindString(t *testing.T) {
+
+	if kind := ValueKind(-1).String(); kind != "ValueKind(-1)" {
+		t.Errorf("Expected ValueKind(-1), Got : %s", kind)
+	}
+	if kind := fmt.Sprint(Value{Kind: ValueKind(100)}.Kind); kind != "ValueKind(100)" {
+		t.Errorf("Expected ValueKind(100), Got : %s", kind)
+	}
+}
+func TestEmptyStringValue(t *testing.T) {
+
+	buf := bytes.NewBufferString(`{"key":""}`)
+	value, err := ParseBuffer(buf)
+	if err != nil {
+		t.Fatalf("Expected valid but got invalid: %s", err)
+	}
+	if key, ok := value.Get("key"); !ok || key.Kind != StringValue || key.Str != "" {
+		t.Errorf("Expected empty string, Got : %v", value.Interface())
+	}
+}
+func TestErrorPosition(t *testing.T) {
+
+	buf := bytes.NewBufferString("{\n  \"key\": \"value\",\n  \"key2\" \"value\"\n}")
+	_, err := ParseBuffer(buf)
+	if err == nil {
+		t.Fatal("Expected invalid but got valid")
+	}
+	if !strings.HasPrefix(err.Error(), "3:10: ") {
+		t.Errorf("Expected error at 3:10, Got : %s", err)
+	}
+}
+func TestSyntaxError(t *testing.T) {
+
+	tests := map[string]SyntaxError{
+		`{"key" "value"}`: {
+			Code: MissingColon, Expected: ":", Actual: `"value"`,
+			Pos: Position{Offset: 7, Line: 1, Column: 8}, End: Position{Offset: 14, Line: 1, Column: 15},
+			Msg: `Expected : but got "value"`,
+		},
+		`["unterminated`: {
+			Code: UnterminatedString, Lexing: true, Expected: `"`, Actual: "EOF",
+			Pos: Position{Offset: 1, Line: 1, Column: 2}, End: Position{Offset: 14, Line: 1, Column: 15},
+			Msg: "Unterminated string",
+		},
+		`["bad \q escape"]`: {
+			Code: InvalidEscape, Lexing: true, Actual: `\q`,
+			Pos: Position{Offset: 6, Line: 1, Column: 7}, End: Position{Offset: 8, Line: 1, Column: 9},
+			Msg: `Invalid escape sequence \q`,
+		},
+	}
+	for input, expected := range tests {
+		err := Validate(strings.NewReader(input))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: Expected a SyntaxError, Got : %v", input, err)
+		} else if *syntaxErr != expected {
+			t.Errorf("%s: Expected %+v, Got : %+v", input, expected, *syntaxErr)
+		}
+	}
+}
+func TestErrorCodes(t *testing.T) {
+
+	tests := map[string]ErrorCode{
+		`[1, 2,]`:         TrailingComma,
+		`{"key": 1,}`:     TrailingComma,
+		`[1 2]`:           MissingComma,
+		`{"a": 1 "b": 2}`: MissingComma,
+		`[1 :]`:           UnexpectedToken,
+		`{1: 2}`:          UnexpectedToken,
+		`{"key" 1}`:       MissingColon,
+		`[1, 2`:           UnexpectedEOF,
+		`{"key"`:          UnexpectedEOF,
+		``:                UnexpectedEOF,
+		`[] []`:           TrailingData,
+		`[01]`:            InvalidNumber,
+		"[\"tab\there\"]": ControlCharacter,
+		`["\ud83d"]`:      LoneSurrogate,
+		`["\u12"]`:        InvalidEscape,
+		"[\"\xff\"]":      InvalidUTF8,
+		`[True]`:          InvalidLiteral,
+		`[@]`:             UnexpectedCharacter,
+	}
+	for input, expected := range tests {
+		err := Validate(strings.NewReader(input))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: Expected a SyntaxError, Got : %v", input, err)
+		} else if syntaxErr.Code != expected {
+			t.Errorf("%q: Expected %s, Got : %s (%s)", input, expected, syntaxErr.Code, err)
+		}
+	}
+	var syntaxErr *SyntaxError
+	err := Options{RFC4627: true}.Validate(strings.NewReader(`"scalar"`))
+	if !errors.As(err, &syntaxErr) || syntaxErr.Code != ScalarTopLevel {
+		t.Errorf("Expected %s, Got : %v", ScalarTopLevel, err)
+	}
+}
+func TestUnexpectedEOF(t *testing.T) {
+
+	for _, input := range []string{`[[]`, `{"key": {}`, `[1,`} {
+		if _, err := ParseBuffer(bytes.NewBufferString(input)); err == nil {
+			t.Errorf("Expected invalid but got valid: %s", input)
+		}
+	}
+}
+func TestTopLevelScalars(t *testing.T) {
+
+	inputs := map[string]any{`"hello"`: "hello", `42`: 42.0, ` null `: nil, `true`: true, "-1.5e1\n": -15.0}
+	for input, expected := range inputs {
+		value, err := ParseBuffer(bytes.NewBufferString(input))
+		if err != nil {
+			t.Errorf("Expected valid but got invalid for %s: %s", input, err)
+			continue
+		}
+		if value.Interface() != expected {
+			t.Errorf("Expected %v, Got : %v", expected, value.Interface())
+		}
+		if _, err := (Options{RFC4627: true}).ParseBuffer(bytes.NewBufferString(input)); err == nil {
+			t.Errorf("Expected invalid in RFC 4627 mode but got valid: %s", input)
+		}
+	}
+	for _, input := range []string{`"a" "b"`, `1 2`, `null,`, `true]`} {
+		if _, err := ParseBuffer(bytes.NewBufferString(input)); err == nil {
+			t.Errorf("Expected invalid but got valid: %s", input)
+		}
+	}
+}
+
+// Produces a large array of objects on the fly so the input never exists in memory as a whole
+type generatedArray struct {
+	remaining int
+	pending   []byte
+}
+
+func (g *generatedArray) Read(p []byte) (int, error) {
+	if len(g.pending) == 0 {
+		switch {
+		case g.remaining < 0:
+			return 0, io.EOF
+		case g.remaining == 0:
+			g.pending = []byte(`{"last": true}]`)
+		default:
+			g.pending = []byte(`{"key": "value", "list": [1, 2.5, null]},`)
+		}
+		g.remaining--
+	}
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	return n, nil
+}
+func TestValidateStreamsInput(t *testing.T) {
+
+	reader := io.MultiReader(strings.NewReader("["), &generatedArray{remaining: 50000})
+	if err := Validate(reader); err != nil {
+		t.Errorf("Expected valid but got invalid: %s", err)
+	}
+	if err := Validate(strings.NewReader(`[{"key": "value"}, {"key" "value"}]`)); err == nil {
+		t.Error("Expected invalid but got valid")
+	}
+}
+func TestParseReader(t *testing.T) {
+
+	value, err := ParseReader(strings.NewReader(`{"list": [1, 2, 3]}`))
+	if err != nil {
+		t.Fatalf("Expected valid but got invalid: %s", err)
+	}
+	if list, _ := value.Get("list"); len(list.Elements) != 3 {
+		t.Errorf("Expected 3 elements, Got : %v", value.Interface())
+	}
+}
+func TestRecovery(t *testing.T) {
+
+	tests := map[string][]string{
+		`[1 2 3]`:                               {"1:4 MissingComma", "1:6 MissingComma"},
+		`{"a": 1, "b": 2,}`:                     {"1:17 TrailingComma"},
+		`{"a": True, "b" 2, "c": [1,], "d": 4}`: {"1:7 InvalidLiteral", "1:17 MissingColon", "1:28 TrailingComma"},
+		`{"a": [1, 2}, "b": 3, 4: 5, "c": 6}`:   {"1:12 UnexpectedToken"},
+		`{"b": 3, 4: 5, "c": 6}`:                {"1:10 UnexpectedToken"},
+		`[1, :, 2, "bad \q", 3`:                 {"1:5 UnexpectedToken", "1:16 InvalidEscape", "1:22 UnexpectedEOF"},
+		`{"a": {"b": [1, 2`:                     {"1:18 UnexpectedEOF"},
+		`[1] [2]`:                               {"1:5 TrailingData"},
+		`[True False, nul]`:                     {"1:2 InvalidLiteral", "1:14 InvalidLiteral"},
+		`{"a":[1}`:                              {"1:8 UnexpectedToken"},
+		`[{"a":1]`:                              {"1:8 UnexpectedToken"},
+		`{"key": "value"}`:                      nil,
+	}
+	for input, expected := range tests {
+		value, err := Options{MaxErrors: 10}.ParseReader(strings.NewReader(input))
+		if expected == nil {
+			if err != nil || value == nil {
+				t.Errorf("%s: Expected valid but got invalid: %s", input, err)
+			}
+			continue
+		}
+		var list ErrorList
+		if !errors.As(err, &list) {
+			t.Errorf("%s: Expected an ErrorList, Got : %v", input, err)
+			continue
+		}
+		got := []string{}
+		for _, syntaxErr := range list {
+			got = append(got, fmt.Sprintf("%s %s", syntaxErr.Pos, syntaxErr.Code))
+		}
+		if strings.Join(got, ", ") != strings.Join(expected, ", ") {
+			t.Errorf("%s: Expected %v, Got : %v", input, expected, got)
+		}
+	}
+}
+func TestRecoveryLimit(t *testing.T) {
+
+	input := `[1 2 3 4 5 6]`
+	err := Options{MaxErrors: 3}.Validate(strings.NewReader(input))
+	var list ErrorList
+	if !errors.As(err, &list) || len(list) != 3 {
+		t.Fatalf("Expected 3 errors, Got : %v", err)
+	}
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr != list[0] {
+		t.Errorf("Expected errors.As to find the first error, Got : %v", syntaxErr)
+	}
+	if err := Validate(strings.NewReader(input)); !errors.As(err, &syntaxErr) || errors.As(err, &list) {
+		t.Errorf("Expected a single SyntaxError without MaxErrors, Got : %v", err)
+	}
+}
+func TestJSON5(t *testing.T) {
+
+	input := `// config
+{
+  name: 'demo',
+  true: 0x10,
+  Infinity: -Infinity,
+  "list": [.5, 5., +1,],
+  nested: {a: null,},
+}`
+	value, err := Options{JSON5: true}.ParseBuffer(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("Expected valid but got invalid: %s", err)
+	}
+	keys := []string{}
+	for _, member := range value.Members {
+		keys = append(keys, member.Key)
+	}
+	if fmt.Sprint(keys) != "[name true Infinity list nested]" {
+		t.Errorf("Expected keys [name true Infinity list nested], Got : %v", keys)
+	}
+	if hex := value.Members[1].Value.Num; hex != 16 {
+		t.Errorf("Expected 16, Got : %v", hex)
+	}
+	if list := fmt.Sprint(value.Members[3].Value.Interface()); list != "[0.5 5 1]" {
+		t.Errorf("Expected [0.5 5 1], Got : %s", list)
+	}
+	if _, err := ParseBuffer(bytes.NewBufferString(input)); err == nil {
+		t.Error("Expected invalid without JSON5 but got valid")
+	}
+
+	inputs := map[string]string{
+		"[1,,]":       "1:4: Error Parsing JSON. Expected value but got ,",
+		"{a:1,,}":     "1:6: Error Parsing JSON. Expected string or identifier but got ,",
+		"{1:1}":       "1:2: Error Parsing JSON. Expected string or identifier but got 1",
+		"{a:b}":       "1:4: Error Parsing JSON. Expected value but got b",
+		"[0x1, 0x2,]": "",
+	}
+	for input, expected := range inputs {
+		err := Options{JSON5: true}.Validate(strings.NewReader(input))
+		if (err == nil && expected != "") || (err != nil && err.Error() != expected) {
+			t.Errorf("Expected %q for %s, Got : %v", expected, input, err)
+		}
+	}
+}
+func TestDuplicateKeys(t *testing.T) {
+
+	input := `{"a": 1, "b": {"a": 2}, "a": 3, "c": 4, "a": 5}`
+	tests := map[DuplicateKeyPolicy]string{
+		AllowDuplicateKeys:    `{"a":1,"b":{"a":2},"a":3,"c":4,"a":5}`,
+		WarnDuplicateKeys:     `{"a":1,"b":{"a":2},"a":3,"c":4,"a":5}`,
+		KeepFirstDuplicateKey: `{"a":1,"b":{"a":2},"c":4}`,
+		KeepLastDuplicateKey:  `{"a":5,"b":{"a":2},"c":4}`,
+	}
+	for policy, expected := range tests {
+		var warnings ErrorList
+		value, err := Options{DuplicateKeys: policy, Warnings: &warnings}.ParseReader(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: Expected valid but got invalid: %s", policy, err)
+		}
+		if data, _ := Marshal(value); string(data) != expected {
+			t.Errorf("%s: Expected %s, Got : %s", policy, expected, data)
+		}
+		if (len(warnings) == 2) != (policy == WarnDuplicateKeys) {
+			t.Errorf("%s: Expected warnings only with %s, Got : %v", policy, WarnDuplicateKeys, warnings)
+		}
+	}
+
+	err := Options{DuplicateKeys: RejectDuplicateKeys}.Validate(strings.NewReader(input))
+	expected := SyntaxError{
+		Code:     DuplicateKey,
+		Actual:   `"a"`,
+		Pos:      Position{24, 1, 25},
+		End:      Position{27, 1, 28},
+		Original: Position{1, 1, 2},
+		Msg:      `Duplicate key "a", first defined at 1:2`,
+	}
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || *syntaxErr != expected {
+		t.Errorf("Expected %+v, Got : %v", expected, err)
+	}
+
+	var warnings ErrorList
+	Options{DuplicateKeys: WarnDuplicateKeys, Warnings: &warnings}.Validate(strings.NewReader(`{"k": 1, "k": 2}`))
+	if len(warnings) != 1 || warnings[0].Error() != `1:10: Warning Parsing JSON. Duplicate key "k", first defined at 1:2` {
+		t.Errorf("Expected a duplicate key warning, Got : %v", warnings)
+	}
+
+	err = Options{DuplicateKeys: RejectDuplicateKeys, MaxErrors: 10}.Validate(strings.NewReader(input))
+	var list ErrorList
+	if !errors.As(err, &list) || len(list) != 2 || list[1].Pos.Column != 41 || list[1].Original.Column != 2 {
+		t.Errorf("Expected both duplicates of the first key, Got : %v", err)
+	}
+	if policy, err := ParseDuplicateKeyPolicy("keep-last"); err != nil || policy != KeepLastDuplicateKey {
+		t.Errorf("Expected %s, Got : %s %v", KeepLastDuplicateKey, policy, err)
+	}
+}
+func TestLimits(t *testing.T) {
+
+	input := `{"name": "abcd", "id": 12345, "tags": ["a", "b"]}`
+	tests := []struct {
+		options  Options
+		code     ErrorCode
+		expected string
+	}{
+		{Options{MaxInputBytes: 20}, InputTooLarge, "1:21: Error Lexing JSON. The input is larger than 20 bytes"},
+		{Options{MaxTokens: 10}, TooManyTokens, "1:37: Error Parsing JSON. The input has more than 10 tokens"},
+		{Options{MaxStringLength: 3}, StringTooLong, "1:2: Error Lexing JSON. Strings can't be longer than 3 bytes"},
+		{Options{MaxNumberLength: 4}, NumberTooLong, "1:24: Error Lexing JSON. Numbers can't be longer than 4 characters"},
+		{Options{MaxMembers: 2}, TooManyMembers, "1:31: Error Parsing JSON. Objects can't have more than 2 members"},
+		{Options{MaxMembers: 2, Iterative: true}, TooManyMembers, "1:31: Error Parsing JSON. Objects can't have more than 2 members"},
+		{Options{MaxStringLength: 3, JSON5: true}, StringTooLong, "1:2: Error Lexing JSON. Strings can't be longer than 3 bytes"},
+	}
+	for _, test := range tests {
+		text := input
+		if test.options.JSON5 {
+			text = `{name: 1}`
+		}
+		err := test.options.Validate(strings.NewReader(text))
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr.Code != test.code || err.Error() != test.expected {
+			t.Errorf("%+v: Expected %q, Got : %v", test.options, test.expected, err)
+		}
+	}
+
+	// everything is allowed right up to the limits
+	options := Options{MaxInputBytes: int64(len(input)), MaxTokens: 17, MaxStringLength: 4, MaxNumberLength: 5, MaxMembers: 3}
+	if err := options.Validate(strings.NewReader(input)); err != nil {
+		t.Errorf("Expected valid but got invalid: %s", err)
+	}
+
+	if _, err := (Options{MaxTokens: 10}).Lex(bytes.NewBufferString(input)); err == nil ||
+		err.Error() != "1:37: Error Lexing JSON. The input has more than 10 tokens" {
+		t.Errorf("Expected Lex to stop after 10 tokens, Got : %v", err)
+	}
+	if tokens, err := (Options{MaxTokens: 17}).Lex(bytes.NewBufferString(input)); err != nil || len(tokens) != 18 {
+		t.Errorf("Expected 17 tokens and EOF, Got : %d %v", len(tokens), err)
+	}
+
+	// the limits stop parsing even when more errors are asked for
+	err := Options{MaxStringLength: 3, MaxErrors: 10}.Validate(strings.NewReader(`[1 2, "abcd", 3 4]`))
+	var list ErrorList
+	if !errors.As(err, &list) || len(list) != 2 || list[1].Code != StringTooLong {
+		t.Errorf("Expected parsing to stop at the string, Got : %v", err)
+	}
+}
+func TestMaxInputBytesStopsReading(t *testing.T) {
+
+	input := &countingReader{r: strings.NewReader("[" + strings.Repeat("1,", 1<<20) + "1]")}
+	err := Options{MaxInputBytes: 100}.Validate(input)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Code != InputTooLarge || syntaxErr.Pos.Offset != 100 {
+		t.Errorf("Expected InputTooLarge at offset 100, Got : %v", err)
+	}
+	if input.n > 101 {
+		t.Errorf("Expected no more than 101 bytes to be read, Got : %d", input.n)
+	}
+}
+
+// Counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
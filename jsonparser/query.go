@@ -0,0 +1,139 @@
+package jsonparser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A step in a Path. Selects the member with the given Key, or the element at Index if IsIndex is set
+type PathElement struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// A list of steps leading from a value to one nested inside it
+type Path []PathElement
+
+// Parses a path written like .users[0].name. Keys that aren't made of letters, digits and
+// underscores can be written as JSON strings in brackets, like .["first name"].
+// An empty path or "." selects the value itself
+func ParsePath(s string) (Path, error) {
+	var path Path
+	rest := strings.TrimPrefix(s, ".")
+	for rest != "" {
+		var element PathElement
+		var err error
+		if rest[0] == '[' {
+			element, rest, err = parseBracket(rest)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid path %q. %w", s, err)
+			}
+		} else {
+			end := 0
+			for end < len(rest) && isLetter(rune(rest[end])) {
+				end++
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("Invalid path %q. Expected key but got %q", s, rest)
+			}
+			element, rest = PathElement{Key: rest[:end]}, rest[end:]
+		}
+		path = append(path, element)
+		if rest != "" && rest[0] == '.' {
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' {
+				return nil, fmt.Errorf("Invalid path %q. Expected key after .", s)
+			}
+		}
+	}
+	return path, nil
+}
+
+// Parses an index like [0] or a quoted key like ["first name"] from the start of s
+func parseBracket(s string) (PathElement, string, error) {
+	if strings.HasPrefix(s, `["`) {
+		// a JSON string ends at the first quote that isn't escaped
+		end := 2
+		for end < len(s) && s[end] != '"' {
+			if s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end+1 >= len(s) || s[end+1] != ']' {
+			return PathElement{}, "", errors.New("Expected quoted key followed by ]")
+		}
+		var key string
+		if err := Unmarshal([]byte(s[1:end+1]), &key); err != nil {
+			return PathElement{}, "", err
+		}
+		return PathElement{Key: key}, s[end+2:], nil
+	}
+	end := strings.IndexByte(s, ']')
+	if end == -1 {
+		return PathElement{}, "", errors.New("Expected ]")
+	}
+	index, err := strconv.Atoi(s[1:end])
+	if err != nil || index < 0 {
+		return PathElement{}, "", fmt.Errorf("Expected array index but got %q", s[1:end])
+	}
+	return PathElement{Index: index, IsIndex: true}, s[end+1:], nil
+}
+
+// Writes the path back out in the form ParsePath reads
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "."
+	}
+	var b strings.Builder
+	for _, element := range p {
+		switch {
+		case element.IsIndex:
+			fmt.Fprintf(&b, "[%d]", element.Index)
+		case element.Key != "" && strings.IndexFunc(element.Key, func(char rune) bool { return !isLetter(char) }) == -1:
+			b.WriteString("." + element.Key)
+		default:
+			data, _ := Marshal(element.Key)
+			fmt.Fprintf(&b, ".[%s]", data)
+		}
+	}
+	return b.String()
+}
+
+// Follows path from v and returns the value it leads to. The error gives the position of the
+// last value reached if a key is missing, an index is out of range or the value has the wrong kind
+func (v *Value) Query(path Path) (*Value, error) {
+	current := v
+	for i, element := range path {
+		var next *Value
+		var ok bool
+		if element.IsIndex {
+			next, ok = current.Index(element.Index)
+		} else {
+			next, ok = current.Get(element.Key)
+		}
+		if !ok {
+			return nil, queryError(current, path[:i+1], element)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+func queryError(v *Value, path Path, element PathElement) error {
+	var message string
+	switch {
+	case element.IsIndex && v.Kind == ArrayValue:
+		message = fmt.Sprintf("Index %d out of range for array of length %d", element.Index, len(v.Elements))
+	case element.IsIndex:
+		message = fmt.Sprintf("Cannot index %s", v.Kind)
+	case v.Kind == ObjectValue:
+		message = fmt.Sprintf("No member with key %q", element.Key)
+	default:
+		message = fmt.Sprintf("Cannot get key %q of %s", element.Key, v.Kind)
+	}
+	return fmt.Errorf("%s: Error Querying JSON. %s at %s", v.Pos, message, path)
+}
@@ -0,0 +1,67 @@
+package jsonparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+
+	tests := map[string]Path{
+		"":                nil,
+		".":               nil,
+		".users[0].name":  {{Key: "users"}, {Index: 0, IsIndex: true}, {Key: "name"}},
+		"users[12]":       {{Key: "users"}, {Index: 12, IsIndex: true}},
+		`.["first name"]`: {{Key: "first name"}},
+		`["a\"]b"].c`:     {{Key: `a"]b`}, {Key: "c"}},
+		".matrix[1][2]":   {{Key: "matrix"}, {Index: 1, IsIndex: true}, {Index: 2, IsIndex: true}},
+	}
+	for input, expected := range tests {
+		path, err := ParsePath(input)
+		if err != nil {
+			t.Errorf("%q: Expected no error, Got : %s", input, err)
+			continue
+		}
+		if path.String() != expected.String() || len(path) != len(expected) {
+			t.Errorf("%q: Expected %s, Got : %s", input, expected, path)
+		}
+	}
+	for _, input := range []string{"..a", ".a.", "[", "[-1]", "[x]", `["a"`, ".a b"} {
+		if _, err := ParsePath(input); err == nil {
+			t.Errorf("%q: Expected error but got none", input)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+
+	value, err := ParseReader(strings.NewReader(`{"users": [{"name": "Ada", "first name": "Ada"}, {"name": "Alan"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		".users[1].name":           "Alan",
+		`.users[0].["first name"]`: "Ada",
+	}
+	for input, expected := range tests {
+		path, _ := ParsePath(input)
+		result, err := value.Query(path)
+		if err != nil {
+			t.Errorf("%q: Expected no error, Got : %s", input, err)
+		} else if result.Str != expected {
+			t.Errorf("%q: Expected %s, Got : %s", input, expected, result.Str)
+		}
+	}
+	errorTests := map[string]string{
+		".users[2]":         "1:11: Error Querying JSON. Index 2 out of range for array of length 2 at .users[2]",
+		".users[0].age":     `1:12: Error Querying JSON. No member with key "age" at .users[0].age`,
+		".users.name":       `1:11: Error Querying JSON. Cannot get key "name" of array at .users.name`,
+		".users[1].name[0]": "1:59: Error Querying JSON. Cannot index string at .users[1].name[0]",
+	}
+	for input, expected := range errorTests {
+		path, _ := ParsePath(input)
+		if _, err := value.Query(path); err == nil || err.Error() != expected {
+			t.Errorf("%q: Expected %s, Got : %v", input, expected, err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package jsonparser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Lines longer than this are cut down to the part around the error
+const maxSnippetWidth = 120
+
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorDim    = "\x1b[2m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+)
+
+// Returns advice on fixing the error for someone editing the JSON by hand, or "" if there
+// is nothing to add to the message
+func (e *SyntaxError) Hint() string {
+	switch e.Code {
+	case TrailingComma:
+		return fmt.Sprintf("trailing comma not allowed before %s", e.Actual)
+	case MissingComma:
+		return "add a comma between the previous value and this one"
+	case MissingColon:
+		return "object keys must be followed by a colon"
+	case UnexpectedEOF:
+		return "the input ended early, check for a missing closing } or ]"
+	case TrailingData:
+		return "only one value is allowed at the top level, wrap several values in an array"
+	case ScalarTopLevel:
+		return "RFC 4627 only allows an object or array at the top level"
+	case InvalidNumber:
+		return "numbers look like 12, -3.5 or 1e10"
+	case UnterminatedString:
+		return `add a closing " to the string`
+	case InvalidEscape:
+		return `the escapes allowed in strings are \" \\ \/ \b \f \n \r \t and \u followed by 4 hex digits`
+	case LoneSurrogate:
+		return `characters outside the Basic Multilingual Plane are written as a pair of escapes like \ud83d\ude00`
+	case ControlCharacter:
+		return `control characters must be escaped, write a newline as \n and a tab as \t`
+	case InvalidUTF8:
+		return "JSON text must be encoded as UTF-8"
+	case InvalidLiteral:
+		if lower := strings.ToLower(e.Actual); lower == "true" || lower == "false" || lower == "null" {
+			return fmt.Sprintf("true, false and null are lowercase, use %s", lower)
+		}
+		return "strings and object keys must be in double quotes"
+	case UnexpectedCharacter:
+		if e.Actual == "'" {
+			return "strings must be in double quotes, not single quotes"
+		}
+		if e.Actual == "/" {
+			return "comments aren't allowed in JSON, use JSONC mode to accept them"
+		}
+	case UnterminatedComment:
+		return "add */ to close the comment"
+	case TooDeep:
+		return "flatten the input, or raise the nesting limit if it really needs to be this deep"
+	case InputTooLarge, TooManyTokens, StringTooLong, NumberTooLong, TooManyMembers:
+		return "the input goes past a limit set for untrusted input, raise the limit if input like this is expected"
+	case DuplicateKey:
+		return fmt.Sprintf("keys must be unique within an object, remove or rename one of the two %s keys", e.Actual)
+	case UnexpectedToken:
+		if e.Expected == STRING.String() {
+			return "object keys must be strings in double quotes"
+		}
+	}
+	return ""
+}
+
+// Writes the error to w prefixed with name, followed by the lines of source around it with a
+// caret under the offending text, and a hint if there is one. source must hold the input the
+// error came from, read from the start. Pass a nil source to leave out the snippet
+func (e *SyntaxError) Render(w io.Writer, name string, source io.Reader, options RenderOptions) error {
+	paint := func(color, s string) string {
+		if !options.Color {
+			return s
+		}
+		return color + s + colorReset
+	}
+	highlight := colorRed
+	if e.Warning {
+		highlight = colorYellow
+	}
+	var b strings.Builder
+	header := e.Error()
+	if name != "" {
+		header = name + ":" + header
+	}
+	b.WriteString(paint(colorBold+highlight, header) + "\n")
+	gutter := 0
+	if source != nil {
+		first := max(1, e.Pos.Line-options.Context)
+		lines, err := readLines(source, first, e.Pos.Line+options.Context)
+		if err != nil {
+			return err
+		}
+		// the error can be at the very end of the input, after the last line break
+		for len(lines) <= e.Pos.Line-first {
+			lines = append(lines, "")
+		}
+		gutter = len(fmt.Sprint(first + len(lines) - 1))
+		start := windowStart(lines[e.Pos.Line-first], e.Pos.Column-1)
+		for i, line := range lines {
+			number := first + i
+			fmt.Fprintf(&b, "%s %s\n", paint(colorDim, fmt.Sprintf("%*d |", gutter, number)), window(line, start))
+			if number == e.Pos.Line {
+				indent, carets := e.caret(line, start)
+				fmt.Fprintf(&b, "%s %s%s\n", paint(colorDim, strings.Repeat(" ", gutter)+" |"), indent, paint(highlight, carets))
+			}
+		}
+	}
+	if hint := e.Hint(); hint != "" {
+		if gutter > 0 {
+			b.WriteString(strings.Repeat(" ", gutter) + " = ")
+		}
+		b.WriteString(paint(colorCyan, "hint: "+hint) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Returns the indent and carets that go under the offending text in line,
+// which is shown from byte start onwards
+func (e *SyntaxError) caret(line string, start int) (string, string) {
+	column := min(e.Pos.Column-1, len(line))
+	end := len(line)
+	if e.End.Line == e.Pos.Line {
+		end = min(e.End.Column-1, len(line))
+	}
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("   ")
+	}
+	// tabs are kept so the caret lines up however wide the terminal shows them
+	for _, char := range line[start:column] {
+		if char == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	width := 1
+	if end > column {
+		width = max(1, utf8.RuneCountInString(line[column:min(end, start+maxSnippetWidth)]))
+	}
+	return b.String(), strings.Repeat("^", width)
+}
+
+// Returns where to start showing a line so that column is in view
+func windowStart(line string, column int) int {
+	if len(line) <= maxSnippetWidth {
+		return 0
+	}
+	start := min(max(0, column-maxSnippetWidth/2), len(line)-maxSnippetWidth)
+	for start > 0 && !utf8.RuneStart(line[start]) {
+		start--
+	}
+	return start
+}
+
+// Cuts line down to at most maxSnippetWidth bytes from start, marking the cuts with ...
+func window(line string, start int) string {
+	if start >= len(line) {
+		if start > 0 {
+			return "..."
+		}
+		return line
+	}
+	shown := line[start:]
+	if start > 0 {
+		shown = "..." + shown
+	}
+	if end := start + maxSnippetWidth; end < len(line) {
+		for end > start && !utf8.RuneStart(line[end]) {
+			end--
+		}
+		shown = shown[:len(shown)-(len(line)-end)] + "..."
+	}
+	return shown
+}
+
+// Returns lines first to last of r, counting from 1, without their line endings
+func readLines(r io.Reader, first, last int) ([]string, error) {
+	reader := bufio.NewReader(r)
+	var lines []string
+	for number := 1; number <= last; number++ {
+		line, err := reader.ReadString('\n')
+		if number >= first && (line != "" || err == nil) {
+			lines = append(lines, strings.TrimRight(line, "\r\n"))
+		}
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
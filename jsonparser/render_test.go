@@ -0,0 +1,68 @@
+package jsonparser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func render(t *testing.T, input string, options RenderOptions) string {
+	t.Helper()
+	var syntaxErr *SyntaxError
+	if err := Validate(strings.NewReader(input)); !errors.As(err, &syntaxErr) {
+		t.Fatalf("Expected a SyntaxError, Got : %v", err)
+	}
+	var out strings.Builder
+	if err := syntaxErr.Render(&out, "config.json", strings.NewReader(input), options); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+func TestRender(t *testing.T) {
+
+	input := "{\n  \"name\": \"app\",\n  \"ports\": [80, 443],\n  \"debug\": true,\n}\n"
+	expected := `config.json:5:1: Error Parsing JSON. Expected string but got }
+3 |   "ports": [80, 443],
+4 |   "debug": true,
+5 | }
+  | ^
+  = hint: trailing comma not allowed before }
+`
+	if got := render(t, input, RenderOptions{Context: 2}); got != expected {
+		t.Errorf("Expected:\n%s\nGot :\n%s", expected, got)
+	}
+}
+func TestRenderSpan(t *testing.T) {
+
+	input := "{\n\t\"enabled\": True\n}"
+	expected := "config.json:2:13: Error Lexing JSON. Unexpected True\n" +
+		"2 | \t\"enabled\": True\n" +
+		"  | \t           ^^^^\n" +
+		"  = hint: true, false and null are lowercase, use true\n"
+	if got := render(t, input, RenderOptions{}); got != expected {
+		t.Errorf("Expected:\n%s\nGot :\n%s", expected, got)
+	}
+}
+func TestRenderLongLine(t *testing.T) {
+
+	input := "[" + strings.Repeat(`"padding", `, 30) + "oops" + strings.Repeat(`, "padding"`, 30) + "]"
+	got := render(t, input, RenderOptions{})
+	lines := strings.Split(got, "\n")
+	if len(lines[1]) > maxSnippetWidth+20 || !strings.HasPrefix(lines[1], "1 | ...") || !strings.HasSuffix(lines[1], "...") {
+		t.Errorf("Expected the line to be cut down, Got : %s", lines[1])
+	}
+	caret := strings.Index(lines[2], "^")
+	if lines[1][caret:caret+4] != "oops" {
+		t.Errorf("Expected caret under oops, Got :\n%s\n%s", lines[1], lines[2])
+	}
+}
+func TestRenderColor(t *testing.T) {
+
+	got := render(t, `[1, 2,]`, RenderOptions{Color: true})
+	if !strings.Contains(got, colorRed+"^"+colorReset) || !strings.Contains(got, colorCyan+"hint: ") {
+		t.Errorf("Expected ANSI colors, Got : %q", got)
+	}
+	if strings.Contains(render(t, `[1, 2,]`, RenderOptions{}), "\x1b[") {
+		t.Error("Expected no ANSI colors without Color")
+	}
+}
@@ -0,0 +1,97 @@
+package jsonparser
+
+import "fmt"
+
+// Kind of a lexed token
+type TokenKind int
+
+const (
+	EOF TokenKind = iota
+	LEFTCURLYBRACE
+	RIGHTCURLYBRACE
+	LEFTSQUAREBRACE
+	RIGHTSQUAREBRACE
+	COLON
+	COMMA
+	STRING
+	NUMBER
+	TRUE
+	FALSE
+	NULL
+	// only returned by the lexer when Options.KeepComments is set
+	COMMENT
+	// an unquoted object key in JSON5 mode. Value holds the name with any escapes decoded
+	IDENTIFIER
+)
+
+var tokenKindNames = [...]string{
+	EOF:              "EOF",
+	LEFTCURLYBRACE:   "{",
+	RIGHTCURLYBRACE:  "}",
+	LEFTSQUAREBRACE:  "[",
+	RIGHTSQUAREBRACE: "]",
+	COLON:            ":",
+	COMMA:            ",",
+	STRING:           "string",
+	NUMBER:           "number",
+	TRUE:             "true",
+	FALSE:            "false",
+	NULL:             "null",
+	COMMENT:          "comment",
+	IDENTIFIER:       "identifier",
+}
+
+func (k TokenKind) String() string {
+	if k < 0 || int(k) >= len(tokenKindNames) {
+		return fmt.Sprintf("TokenKind(%d)", int(k))
+	}
+	return tokenKindNames[k]
+}
+
+var punctuation = map[rune]TokenKind{
+	'{': LEFTCURLYBRACE,
+	'}': RIGHTCURLYBRACE,
+	'[': LEFTSQUAREBRACE,
+	']': RIGHTSQUAREBRACE,
+	':': COLON,
+	',': COMMA,
+}
+
+var keywords = map[string]TokenKind{
+	"true":  TRUE,
+	"false": FALSE,
+	"null":  NULL,
+}
+
+// Location of a token in the input. Line and Column start at 1, Column counts bytes
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Returns the position n bytes further along the same line
+func (p Position) add(n int) Position {
+	return Position{Offset: p.Offset + n, Line: p.Line, Column: p.Column + n}
+}
+
+// A lexed token. Text is the token exactly as it appears in the input and Value is its literal value,
+// i.e. the contents of a string without the quotes. The token spans from Pos up to but not including End
+type Token struct {
+	Kind  TokenKind
+	Text  string
+	Value string
+	Pos   Position
+	End   Position
+}
+
+func (t Token) String() string {
+	if t.Kind == EOF {
+		return t.Kind.String()
+	}
+	return t.Text
+}
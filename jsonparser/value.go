@@ -0,0 +1,112 @@
+package jsonparser
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Kind of JSON value held by a Value
+type ValueKind int
+
+const (
+	NullValue ValueKind = iota
+	BoolValue
+	NumberValue
+	StringValue
+	ArrayValue
+	ObjectValue
+)
+
+var valueKindNames = [...]string{
+	NullValue:   "null",
+	BoolValue:   "bool",
+	NumberValue: "number",
+	StringValue: "string",
+	ArrayValue:  "array",
+	ObjectValue: "object",
+}
+
+func (k ValueKind) String() string {
+	if k < 0 || int(k) >= len(valueKindNames) {
+		return fmt.Sprintf("ValueKind(%d)", int(k))
+	}
+	return valueKindNames[k]
+}
+
+// A key/value pair of an object. Members are kept in the order they appear in the input
+type Member struct {
+	Key   string
+	Value *Value
+}
+
+// A node in the tree built by Parse. Only the field matching Kind is set, except for numbers
+// which set both Num and Number. Pos is where the value starts in the input
+type Value struct {
+	Kind ValueKind
+	Pos  Position
+	Bool bool
+	// The float64 nearest to the number, which is ±Inf when it's out of range
+	Num float64
+	// The number exactly as it was written. Marshal writes it out unchanged, and its methods
+	// convert it without losing precision. Values built by hand can leave it empty to use Num
+	Number   Number
+	Str      string
+	Elements []*Value
+	Members  []Member
+}
+
+// Returns the value of the first member with the given key.
+// ok is false if v is not an object or has no such member
+func (v *Value) Get(key string) (value *Value, ok bool) {
+	if v.Kind != ObjectValue {
+		return nil, false
+	}
+	for _, member := range v.Members {
+		if member.Key == key {
+			return member.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Returns the i-th element of an array. ok is false if v is not an array or i is out of range
+func (v *Value) Index(i int) (value *Value, ok bool) {
+	if v.Kind != ArrayValue || i < 0 || i >= len(v.Elements) {
+		return nil, false
+	}
+	return v.Elements[i], true
+}
+
+// Returns the exact number, or Num written out for a Value built without one
+func (v *Value) number() Number {
+	if v.Number != "" {
+		return v.Number
+	}
+	return Number(strconv.FormatFloat(v.Num, 'g', -1, 64))
+}
+
+// Converts a JSON value back into its Go equivalent:
+// map[string]any, []any, string, float64, bool or nil
+func (v *Value) Interface() any {
+	switch v.Kind {
+	case BoolValue:
+		return v.Bool
+	case NumberValue:
+		return v.Num
+	case StringValue:
+		return v.Str
+	case ArrayValue:
+		elements := make([]any, len(v.Elements))
+		for i, element := range v.Elements {
+			elements[i] = element.Interface()
+		}
+		return elements
+	case ObjectValue:
+		members := make(map[string]any, len(v.Members))
+		for _, member := range v.Members {
+			members[member.Key] = member.Value.Interface()
+		}
+		return members
+	}
+	return nil
+}
@@ -1,332 +1,474 @@
 package main
 
 import (
-	"bufio"
-	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
-	"slices"
-	"strconv"
-	"unicode"
-	"unicode/utf8"
-)
+	"runtime"
+	"strings"
 
-type lexnexttokenparams struct {
-	lineTokens       *[]string
-	token            *string
-	prevToken        *string
-	prevChar         *rune
-	char             *rune
-	isLexingString   *bool
-	prevTokenIsQuote *bool
-}
+	"json-parser/jsonparser"
+)
 
+// Exit codes, so scripts can tell invalid JSON apart from being called wrongly
 const (
-	LEFTCURLYBRACE   = "{"
-	RIGHTCURLYBRACE  = "}"
-	LEFTSQUAREBRACE  = "["
-	RIGHTSQUAREBRACE = "]"
-	QUOTE            = "\""
-	TRUE             = "true"
-	FALSE            = "false"
-	NULL             = "null"
-	COLON            = ":"
-	COMMA            = ","
+	exitValid   = 0
+	exitInvalid = 1
+	exitUsage   = 2
 )
 
-var staticTokens = []string{LEFTCURLYBRACE, RIGHTCURLYBRACE, LEFTSQUAREBRACE, RIGHTSQUAREBRACE, QUOTE, COLON, TRUE, FALSE, NULL, COMMA}
-
-func readJson() *bytes.Buffer {
+// An error that isn't about the JSON itself, like a bad flag or a file that can't be opened
+type usageError struct {
+	err error
+}
 
-	var fileName string
-	var buf *bytes.Buffer = bytes.NewBuffer(make([]byte, 0))
-	flag.StringVar(&fileName, "file", "", "Path to JSON file")
-	flag.Parse()
-	if fileName != "" {
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+func (e usageError) Unwrap() error {
+	return e.err
+}
 
-		openFile, err := os.Open(fileName)
+// An error in an input, shown with the input's name in front
+type inputError struct {
+	name string
+	err  error
+	// Opens the input again to show the lines around the error. nil if it can't be read twice, like stdin
+	source func() (io.ReadCloser, error)
+}
 
-		_, copyErr := io.Copy(buf, openFile)
-		handleFileReadError("Unable to read file ", err)
-		handleFileReadError("Error opening file "+fileName, copyErr)
-		defer openFile.Close()
-	} else if jsonString := flag.Arg(0); jsonString == "" && fileName == "" {
-		_, err := io.Copy(buf, os.Stdin)
-		handleFileReadError("Unable to read from Stdin", err)
-	} else {
-		buf = bytes.NewBufferString(jsonString)
+func (e inputError) Error() string {
+	// errors already start with line:col so this gives file:line:col, on every line when there are several
+	lines := strings.Split(e.err.Error(), "\n")
+	for i, line := range lines {
+		lines[i] = e.name + ":" + line
 	}
-	return buf
+	return strings.Join(lines, "\n")
 }
-func main() {
-	fmt.Println(ParseJson())
-}
-func ParseJson() error {
-	json := readJson()
-	tokens := slices.Concat(Lex(json)...)
-	return Parse(&tokens)
+func (e inputError) Unwrap() error {
+	return e.err
 }
 
-// Function to extract JSON tokens from a buffer
-func Lex(buf *bytes.Buffer) [][]string {
-
-	lineScanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
-	lineScanner.Split(bufio.ScanLines)
-	tokens := [][]string{}
-	for lineScanner.Scan() {
-		runeScanner := bufio.NewScanner(bytes.NewReader(lineScanner.Bytes()))
-		runeScanner.Split(bufio.ScanRunes)
-		lineTokens := []string{}
-		token := ""
-		prevToken := ""
-		prevChar := rune(0)
-		isLexingNumber := false
-		isLexingString := false
-		for runeScanner.Scan() {
-			scannedBytes := runeScanner.Bytes()
-			char, _ := utf8.DecodeRune(scannedBytes)
-			prevTokenIsQuote := prevToken == "\""
-			if char == '"' && !isLexingString {
-				isLexingString = true
-			}
-			//skip spaces that do not exist within a string
-			if unicode.IsSpace(char) && !isLexingString {
-				prevChar = char
-				saveToken(&token, &lineTokens, &prevToken)
-				continue
-			}
-			// save value string token when we reach closing quote. handles escaped quotes
-			// TODO: This a naive implementation as it doesn't cover the edge case where the string "\\" is read as one token instead of 3
-			if isLexingString && char == '"' && prevChar != rune(0) && prevChar != '\\' && prevTokenIsQuote {
-				isLexingString = false
-				lexNextToken(true, lexnexttokenparams{&lineTokens, &token, &prevToken, &prevChar, &char, &isLexingString, &prevTokenIsQuote})
-				continue
-			}
-			//stop lexing key string after reaching end quote
-			if char == ':' && prevTokenIsQuote {
-				isLexingString = false
-				lexNextToken(false, lexnexttokenparams{&lineTokens, &token, &prevToken, &prevChar, &char, &isLexingString, &prevTokenIsQuote})
-				continue
-			}
-			isNegative := prevChar == '-' && unicode.IsNumber(char)
-			if !isLexingString && (isNegative || unicode.IsNumber(char)) {
-				isLexingNumber = true
-				lexNextToken(false, lexnexttokenparams{&lineTokens, &token, &prevToken, &prevChar, &char, &isLexingString, &prevTokenIsQuote})
-				continue
-			}
-			isLexingFloat := char == '.'
-			isLexingExponent := isExponent(char)
-			isLexingExponentSign := isExponent(prevChar) && (char == '+' || char == '-')
-			if isLexingNumber && !isLexingFloat && !isLexingExponent && !isLexingExponentSign && !unicode.IsNumber(char) {
-				isLexingNumber = false
-				lexNextToken(true, lexnexttokenparams{&lineTokens, &token, &prevToken, &prevChar, &char, &isLexingString, &prevTokenIsQuote})
-				continue
-			}
-			lexNextToken(false, lexnexttokenparams{&lineTokens, &token, &prevToken, &prevChar, &char, &isLexingString, &prevTokenIsQuote})
-		}
-		// save the last token that was accumulated
-		saveToken(&token, &lineTokens, &prevToken)
-		tokens = append(tokens, lineTokens)
-	}
-	return tokens
+// Wraps the worst of the per-file errors from validate, which have already been reported
+type fileResultsError struct {
+	worst error
 }
 
-// only save static tokens that are not part of a string.
-// updates token and prevChar
-func lexNextToken(saveNonStaticToken bool, params lexnexttokenparams) {
-	// De Morgan's Law to the rescue. second condition was previously !(token !="\"" && isLexingString && prevTokenIsQuote)
-	isStaticToken := slices.Contains(staticTokens, *params.token) && (*params.token == "\"" || !*params.isLexingString || !*params.prevTokenIsQuote)
-	if saveNonStaticToken || isStaticToken {
-		saveToken(params.token, params.lineTokens, params.prevToken)
-	}
-	*params.token += string(*params.char)
-	*params.prevChar = *params.char
+func (e fileResultsError) Error() string {
+	return ""
+}
+func (e fileResultsError) Unwrap() error {
+	return e.worst
 }
-func Parse(tokens *[]string) error {
 
-	// in recursive descent parsers we write a method to match each "entity " in the string
-	// we also have methods that implement a production rule in the grammar, so basically we need function to match:
-	// keyword tokens, numbers, strings, objects, and arrays
-	pos := -1
-	if len(*tokens) == 0 {
-		return fmt.Errorf("Expected tokens but found nil")
-	}
-	lastToken := (*tokens)[len(*tokens)-1]
-	switch (*tokens)[pos+1] {
-	case LEFTCURLYBRACE:
-		if lastToken != RIGHTCURLYBRACE {
-			return parserError(len(*tokens)-1, "}", lastToken)
-		}
-		if _, err := parseObject(tokens, &pos, true); err != nil {
-			return err
-		}
-		return nil
-	case LEFTSQUAREBRACE:
+// Flags every command accepts
+type commonFlags struct {
+	files   []string
+	quiet   bool
+	color   string
+	options jsonparser.Options
+	// filled in through options.Warnings by commands that read a single input
+	warnings jsonparser.ErrorList
+}
 
-		if lastToken != RIGHTSQUAREBRACE {
-			return parserError(len(*tokens)-1, "}", lastToken)
-		}
-		if _, err := parseArray(tokens, &pos, true); err != nil {
-			return err
-		}
+// Adds the flags every command accepts to flags
+func addCommonFlags(flags *flag.FlagSet) *commonFlags {
+	var common commonFlags
+	common.options.Warnings = &common.warnings
+	flags.Func("file", "Path to JSON file. validate accepts it more than once, along with globs and directories", func(path string) error {
+		common.files = append(common.files, path)
 		return nil
+	})
+	flags.BoolVar(&common.quiet, "quiet", false, "Don't print errors or the valid message, only set the exit code")
+	flags.StringVar(&common.color, "color", "auto", "Highlight errors: auto to only highlight on a terminal, always or never")
+	flags.BoolVar(&common.options.RFC4627, "rfc4627", false, "Only accept an object or array at the top level")
+	flags.BoolVar(&common.options.JSONC, "jsonc", false, "Allow // and /* */ comments like VS Code settings and tsconfig files")
+	flags.BoolVar(&common.options.JSON5, "json5", false, "Accept JSON5, with unquoted keys, single quoted strings, trailing commas, hex numbers and comments")
+	flags.Func("duplicate-keys", "What to do with a key that appears twice in an object: allow, error, warn, keep-first or keep-last (default allow)", func(name string) error {
+		policy, err := jsonparser.ParseDuplicateKeyPolicy(name)
+		common.options.DuplicateKeys = policy
+		return err
+	})
+	flags.IntVar(&common.options.MaxDepth, "max-depth", jsonparser.DefaultMaxDepth, "How deeply objects and arrays can be nested, -1 for no limit")
+	flags.Int64Var(&common.options.MaxInputBytes, "max-input-bytes", 0, "Reject input larger than this many bytes without reading past them, 0 for no limit")
+	flags.IntVar(&common.options.MaxTokens, "max-tokens", 0, "Reject input with more tokens than this, 0 for no limit")
+	flags.IntVar(&common.options.MaxStringLength, "max-string-length", 0, "Reject strings longer than this many bytes, 0 for no limit")
+	flags.IntVar(&common.options.MaxNumberLength, "max-number-length", 0, "Reject numbers longer than this many characters, 0 for no limit")
+	flags.IntVar(&common.options.MaxMembers, "max-members", 0, "Reject objects with more members than this, 0 for no limit")
+	flags.IntVar(&common.options.MaxErrors, "max-errors", 1, "Carry on after a syntax error to report up to this many of them")
+	return &common
+}
 
+// Opens the file given with --file, or else the JSON string passed as the positional argument
+// at index arg, or else stdin. Returns the input along with a name for it to use in error messages.
+// The input is left to be streamed, and --max-input-bytes stops the lexer reading it at the limit
+func (c *commonFlags) readJson(flags *flag.FlagSet, arg int) (string, io.ReadCloser, error) {
+	if len(c.files) > 1 {
+		return "", nil, usageError{fmt.Errorf("%s reads a single file but --file was given %d times", flags.Name(), len(c.files))}
+	}
+	if len(c.files) == 1 {
+		return openJson(c.files[0])
+	}
+	if jsonString := flags.Arg(arg); jsonString != "" {
+		return "<arg>", io.NopCloser(strings.NewReader(jsonString)), nil
 	}
-	updatePos(&pos)
-	return fmt.Errorf("Invalid JSON string. Expected { or [, got %s", (*tokens)[pos])
+	return "<stdin>", os.Stdin, nil
 }
-func parseObject(tokens *[]string, pos *int, isOuterObject bool) (bool, error) {
 
-	for {
-		updatePos(pos)
-		switch (*tokens)[*pos+1] {
-		case RIGHTCURLYBRACE:
-			if matchComma((*tokens)[*pos]) {
-				return false, parserError(*pos, "token", "}")
-			}
-			return true, nil
-		case QUOTE:
-			if _, err := parseString(tokens, pos); err != nil {
-				return false, err
-			}
-		default:
-			return false, parserError(*pos, "\"", (*tokens)[*pos+1])
-		}
-		updatePos(pos)
-		if (*tokens)[*pos+1] != COLON {
-			return false, parserError(*pos, ":", (*tokens)[*pos+1])
-		}
-		updatePos(pos)
-		if _, err := parseValues(tokens, pos); err != nil {
-			return false, err
-		}
-		if ret, err := parseValueEnding((*tokens)[*pos+1], RIGHTCURLYBRACE, *pos+1, isOuterObject, len(*tokens)); ret || err != nil {
-			return ret, err
-		}
+// Wraps an error in the input chosen by readJson so that it can be shown along with
+// the lines of input around it
+func (c *commonFlags) inputError(flags *flag.FlagSet, arg int, name string, err error) error {
+	inputErr := inputError{name: name, err: err}
+	if len(c.files) == 1 {
+		inputErr.source = func() (io.ReadCloser, error) { return os.Open(c.files[0]) }
+	} else if jsonString := flags.Arg(arg); jsonString != "" {
+		inputErr.source = func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(jsonString)), nil }
 	}
+	return inputErr
+}
 
+// Prints the warnings found in the input chosen by readJson, unless --quiet is given
+func (c *commonFlags) printWarnings(flags *flag.FlagSet, arg int, name string) {
+	if len(c.warnings) > 0 && !c.quiet {
+		printError(c.inputError(flags, arg, name, c.warnings), c.useColor())
+	}
 }
 
-// Parses the tokens before a comma in an object or array.
-//
-// Returns: bool specifying whether to return from calling function and Error value
-func parseValueEnding(currentToken string, TOKEN string, pos int, isParent bool, tokensLength int) (bool, error) {
+// Reports whether errors should be highlighted with ANSI escape codes, following --color.
+// auto highlights them when stderr is a terminal and NO_COLOR isn't set
+func (c *commonFlags) useColor() bool {
+	switch c.color {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0 && os.Getenv("NO_COLOR") == ""
+}
 
-	if currentToken != COMMA {
-		if currentToken != TOKEN {
-			return false, parserError(pos, TOKEN, currentToken)
-		}
-		if isParent && pos != tokensLength-1 {
-			return false, parserError(pos, "EOF", currentToken)
-		}
-		return true, nil // at this point we want to stop parsing the object or array
+func openJson(fileName string) (string, io.ReadCloser, error) {
+	openFile, err := os.Open(fileName)
+	if err != nil {
+		return fileName, nil, usageError{fmt.Errorf("Error opening file %s: %w", fileName, err)}
 	}
-	return false, nil
+	return fileName, openFile, nil
 }
-func parseArray(tokens *[]string, pos *int, isOuterArray bool) (bool, error) {
 
-	for {
-		updatePos(pos)
+type command struct {
+	args    string
+	summary string
+	// Adds the command's own flags and returns the function that runs it once they are parsed
+	setup func(flags *flag.FlagSet, common *commonFlags) func() error
+}
 
-		if (*tokens)[*pos+1] == RIGHTSQUAREBRACE {
-			if matchComma((*tokens)[*pos]) {
-				return false, parserError(*pos, "token", "]")
-			}
+var commands = map[string]command{
+	"validate": {"[json]", "Check that the input is valid JSON (the default command)", validateCommand},
+	"format":   {"[json]", "Pretty-print the input", formatCommand},
+	"minify":   {"[json]", "Write the input out without whitespace", minifyCommand},
+	"query":    {"<path> [json]", "Print the value at a path like .users[0].name", queryCommand},
+	"convert":  {"[json5]", "Convert JSON5 to JSON", convertCommand},
+}
+var commandNames = []string{"validate", "format", "minify", "query", "convert"}
 
-			return true, nil
-		}
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
 
-		if _, err := parseValues(tokens, pos); err != nil {
-			return false, err
+// Runs the command named by the first argument, or validate if it doesn't name one,
+// and returns the exit code
+func run(args []string) int {
+	name := "validate"
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			name, args = args[0], args[1:]
 		}
-		if ret, err := parseValueEnding((*tokens)[*pos+1], RIGHTSQUAREBRACE, *pos+1, isOuterArray, len(*tokens)); ret || err != nil {
-			return ret, err
+	}
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() { usage(flags) }
+	common := addCommonFlags(flags)
+	runCommand := commands[name].setup(flags, common)
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitValid
 		}
+		return exitUsage
 	}
+	if common.color != "auto" && common.color != "always" && common.color != "never" {
+		fmt.Fprintf(os.Stderr, "Unknown --color %q, expected auto, always or never\n", common.color)
+		return exitUsage
+	}
+	// nesting deeper than the default is parsed without recursion, so it can't exhaust the stack
+	common.options.Iterative = common.options.MaxDepth < 0 || common.options.MaxDepth > jsonparser.DefaultMaxDepth
+	err := runCommand()
+	var printed fileResultsError
+	if err != nil && !common.quiet && !errors.As(err, &printed) {
+		printError(err, common.useColor())
+	}
+	return exitCode(err)
 }
 
-// Parse out a string,object,number, or array
-func parseValues(tokens *[]string, pos *int) (bool, error) {
-	switch (*tokens)[*pos+1] {
-	case LEFTCURLYBRACE:
-		if _, err := parseObject(tokens, pos, false); err != nil {
-			return false, err
-		}
-	case LEFTSQUAREBRACE:
-		if _, err := parseArray(tokens, pos, false); err != nil {
-			return false, err
+// Prints err to stderr. Syntax errors are shown with the lines of input around them
+// and a hint when the input can be read again
+func printError(err error, color bool) {
+	var inputErr inputError
+	syntaxErrs := syntaxErrors(err)
+	if !errors.As(err, &inputErr) || len(syntaxErrs) == 0 {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	options := jsonparser.RenderOptions{Context: 2, Color: color}
+	for _, syntaxErr := range syntaxErrs {
+		var source io.ReadCloser
+		if inputErr.source != nil {
+			source, _ = inputErr.source()
 		}
-	case QUOTE:
-		if _, err := parseString(tokens, pos); err != nil {
-			return false, err
+		// the input is read again for each error, as there is no telling how far apart they are
+		var renderErr error
+		if source != nil {
+			renderErr = syntaxErr.Render(os.Stderr, inputErr.name, source, options)
+			source.Close()
+		} else {
+			renderErr = syntaxErr.Render(os.Stderr, inputErr.name, nil, options)
 		}
-	case TRUE, FALSE, NULL:
-	default:
-		if !matchNumber((*tokens)[*pos+1]) {
-			return false, parserError(*pos, "token", (*tokens)[*pos+1])
+		if renderErr != nil {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", inputErr.name, syntaxErr)
 		}
 	}
-	updatePos(pos)
-	return true, nil
 }
-func parseString(tokens *[]string, pos *int) (bool, error) {
-	updatePos(pos)
-	if !matchQuote((*tokens)[*pos+1]) {
-		updatePos(pos)
-		if !matchQuote((*tokens)[*pos+1]) {
-			return false, parserError(*pos, "\"", (*tokens)[*pos])
-		}
+
+// Returns the syntax errors in err, which holds several of them when --max-errors is more than 1
+func syntaxErrors(err error) []*jsonparser.SyntaxError {
+	var list jsonparser.ErrorList
+	var syntaxErr *jsonparser.SyntaxError
+	switch {
+	case errors.As(err, &list):
+		return list
+	case errors.As(err, &syntaxErr):
+		return []*jsonparser.SyntaxError{syntaxErr}
 	}
-	return true, nil
-}
-func updatePos(pos *int) {
-	*pos += 1
+	return nil
 }
-func matchComma(token string) bool {
-	return matchKeyword(token, COMMA)
-}
-func matchQuote(token string) bool {
-	return matchKeyword(token, QUOTE)
-}
-func matchNumber(token string) bool {
-	_, intErr := strconv.ParseInt(token, 10, 64)
-	_, floatErr := strconv.ParseFloat(token, 64)
-	if intErr != nil && floatErr != nil {
-		return false
+
+func exitCode(err error) int {
+	var usageErr usageError
+	var pathErr *fs.PathError
+	switch {
+	case err == nil:
+		return exitValid
+	case errors.As(err, &usageErr), errors.As(err, &pathErr):
+		return exitUsage
 	}
-	return true
+	return exitInvalid
 }
 
-func matchKeyword(token string, keyword string) bool {
-	if token == keyword {
-		return true
+func usage(flags *flag.FlagSet) {
+	out := flags.Output()
+	fmt.Fprintf(out, "Usage: jsonparse [command] [flags] %s\n\nCommands:\n", commands[flags.Name()].args)
+	for _, name := range commandNames {
+		fmt.Fprintf(out, "  %-10s%s\n", name, commands[name].summary)
 	}
-	return false
+	fmt.Fprintf(out, "\nFlags for %s:\n", flags.Name())
+	flags.PrintDefaults()
+	fmt.Fprintf(out, "\nExit codes: %d valid, %d invalid, %d usage or I/O error\n", exitValid, exitInvalid, exitUsage)
 }
 
-func parserError(pos int, expected string, got string) error {
-	return fmt.Errorf("Error Parsing JSON at %d. Expected %s but got %s", pos, expected, got)
+// Validates the input as it is read rather than loading all of it into memory first.
+// Several files can be given with --file, in which case they are validated concurrently
+func validateCommand(flags *flag.FlagSet, common *commonFlags) func() error {
+	var finder fileFinder
+	flags.BoolVar(&finder.recursive, "recursive", false, "Also search the subdirectories of directories given with --file")
+	flags.Func("include", "Only check files in directories whose name or path matches this glob, can be repeated (default *.json)", func(pattern string) error {
+		finder.include = append(finder.include, pattern)
+		return nil
+	})
+	flags.Func("exclude", "Skip files and directories whose name or path matches this glob, can be repeated", func(pattern string) error {
+		finder.exclude = append(finder.exclude, pattern)
+		return nil
+	})
+	jobs := flags.Int("jobs", runtime.NumCPU(), "Number of files to validate at the same time")
+	outputFormat := flags.String("output-format", "text", "How to report results: text, json, sarif or junit")
+	return func() error {
+		if *jobs < 1 {
+			return usageError{errors.New("--jobs must be at least 1")}
+		}
+		writeReport, ok := outputFormats[*outputFormat]
+		if !ok {
+			return usageError{fmt.Errorf("Unknown output format %q, expected text, json, sarif or junit", *outputFormat)}
+		}
+		var results []fileResult
+		report := func(r fileResult) {
+			results = append(results, r)
+			if *outputFormat == "text" && !common.quiet {
+				printTextResult(r, common.useColor())
+			}
+		}
+		if len(common.files) == 0 {
+			name, json, err := common.readJson(flags, 0)
+			if err != nil {
+				return err
+			}
+			defer json.Close()
+			result := fileResult{file: name}
+			if err := common.options.Validate(json); err != nil {
+				result.err = common.inputError(flags, 0, name, err)
+			}
+			if len(common.warnings) > 0 {
+				result.warnings = common.inputError(flags, 0, name, common.warnings)
+			}
+			report(result)
+		} else {
+			files, err := finder.find(common.files)
+			if err != nil {
+				return usageError{err}
+			}
+			validateFiles(common.options, files, *jobs, report)
+		}
+		if *outputFormat != "text" || !common.quiet {
+			if err := writeReport(os.Stdout, results); err != nil {
+				return err
+			}
+		}
+		var worst error
+		for _, r := range results {
+			if exitCode(r.err) > exitCode(worst) {
+				worst = r.err
+			}
+		}
+		if worst == nil {
+			return nil
+		}
+		return fileResultsError{worst}
+	}
 }
 
-// lexing functions
-
-func isExponent(char rune) bool {
-	return (char == 'e' || char == 'E')
+// Validates files using up to jobs goroutines and calls report with each result
+// in the order the files were given
+func validateFiles(options jsonparser.Options, files []string, jobs int, report func(fileResult)) {
+	results := make([]chan fileResult, len(files))
+	limit := make(chan struct{}, jobs)
+	for i, file := range files {
+		results[i] = make(chan fileResult, 1)
+		go func() {
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			_, json, err := openJson(file)
+			if err != nil {
+				results[i] <- fileResult{file: file, err: err}
+				return
+			}
+			defer json.Close()
+			// each file needs its own list of warnings
+			var warnings jsonparser.ErrorList
+			options := options
+			options.Warnings = &warnings
+			source := func() (io.ReadCloser, error) { return os.Open(file) }
+			result := fileResult{file: file}
+			if err := options.Validate(json); err != nil {
+				result.err = inputError{file, err, source}
+			}
+			if len(warnings) > 0 {
+				result.warnings = inputError{file, warnings, source}
+			}
+			results[i] <- result
+		}()
+	}
+	for i := range files {
+		report(<-results[i])
+	}
 }
-func saveToken(token *string, lineTokens *[]string, prevToken *string) {
-	if *token != "" {
-		*lineTokens = append(*lineTokens, *token)
-		*prevToken = *token
-		*token = ""
+
+// Parses the input and writes it back out to stdout indented
+func formatCommand(flags *flag.FlagSet, common *commonFlags) func() error {
+	indent := flags.Int("indent", 2, "Number of spaces to indent each level by, 0 puts everything on one line")
+	tabs := flags.Bool("tabs", false, "Indent each level by a tab instead of spaces")
+	sortKeys := flags.Bool("sort-keys", false, "Sort object members by key")
+	return func() error {
+		if *indent < 0 {
+			return usageError{errors.New("--indent must not be negative")}
+		}
+		name, json, err := common.readJson(flags, 0)
+		if err != nil {
+			return err
+		}
+		defer json.Close()
+		value, err := common.options.ParseReader(json)
+		common.printWarnings(flags, 0, name)
+		if err != nil {
+			return common.inputError(flags, 0, name, err)
+		}
+		encodeOptions := jsonparser.EncodeOptions{Indent: strings.Repeat(" ", *indent), SortKeys: *sortKeys}
+		if *tabs {
+			encodeOptions.Indent = "\t"
+		}
+		return encodeOptions.Encode(os.Stdout, value)
 	}
+}
 
+// Writes the input back out to stdout without any whitespace between tokens. The input is
+// streamed through rather than parsed into a Value so large files don't need to fit in memory
+func minifyCommand(flags *flag.FlagSet, common *commonFlags) func() error {
+	flags.BoolVar(&common.options.KeepComments, "keep-comments", false, "Keep comments in the output, with --jsonc or --json5")
+	return func() error {
+		name, json, err := common.readJson(flags, 0)
+		if err != nil {
+			return err
+		}
+		defer json.Close()
+		err = common.options.Minify(os.Stdout, json)
+		common.printWarnings(flags, 0, name)
+		if err != nil {
+			return common.inputError(flags, 0, name, err)
+		}
+		fmt.Println()
+		return nil
+	}
 }
-func handleFileReadError(errMsg string, err error) {
-	if err != nil {
-		io.WriteString(os.Stderr, fmt.Sprintf("%s: %s \n", errMsg, err))
-		os.Exit(1)
+
+// Writes JSON5 input back out as compact JSON. Like minify the input is streamed through
+func convertCommand(flags *flag.FlagSet, common *commonFlags) func() error {
+	return func() error {
+		name, json, err := common.readJson(flags, 0)
+		if err != nil {
+			return err
+		}
+		defer json.Close()
+		err = common.options.JSON5ToJSON(os.Stdout, json)
+		common.printWarnings(flags, 0, name)
+		if err != nil {
+			return common.inputError(flags, 0, name, err)
+		}
+		fmt.Println()
+		return nil
 	}
+}
 
+// Prints the value found by following a path from the top level value of the input
+func queryCommand(flags *flag.FlagSet, common *commonFlags) func() error {
+	return func() error {
+		if flags.NArg() == 0 {
+			return usageError{errors.New("query needs a path, for example .users[0].name")}
+		}
+		path, err := jsonparser.ParsePath(flags.Arg(0))
+		if err != nil {
+			return usageError{err}
+		}
+		name, json, err := common.readJson(flags, 1)
+		if err != nil {
+			return err
+		}
+		defer json.Close()
+		value, err := common.options.ParseReader(json)
+		common.printWarnings(flags, 1, name)
+		if err != nil {
+			return common.inputError(flags, 1, name, err)
+		}
+		if value, err = value.Query(path); err != nil {
+			return inputError{name: name, err: err}
+		}
+		return jsonparser.EncodeOptions{Indent: "  "}.Encode(os.Stdout, value)
+	}
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Calls run with stdout and stderr sent to temporary files and returns the exit code
+// along with what was written to each
+func runCaptured(t *testing.T, args ...string) (int, string, string) {
+	dir := t.TempDir()
+	capture := func(name string) *os.File {
+		file, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	stdout, stderr := capture("stdout"), capture("stderr")
+	defer stdout.Close()
+	defer stderr.Close()
+	realStdout, realStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	code := run(args)
+	os.Stdout, os.Stderr = realStdout, realStderr
+
+	read := func(file *os.File) string {
+		out, err := os.ReadFile(file.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(out)
+	}
+	return code, read(stdout), read(stderr)
+}
+
+func TestRun(t *testing.T) {
+
+	valid := filepath.Join(t.TempDir(), "valid.json")
+	if err := os.WriteFile(valid, []byte(`{"a": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"valid", []string{`{"a": 1}`}, exitValid, "<arg>: valid\n", ""},
+		{"invalid", []string{`{"a": }`}, exitInvalid, "", "<arg>:1:7: Error Parsing JSON. Expected value but got }"},
+		{"valid file", []string{"--file", valid}, exitValid, valid + ": valid\n", ""},
+		{"quiet valid", []string{"--quiet", `{"a": 1}`}, exitValid, "", ""},
+		{"quiet invalid", []string{"--quiet", `{"a": }`}, exitInvalid, "", ""},
+		{"missing file", []string{"--file", missing}, exitUsage, "", "Error opening file " + missing},
+		{"missing file for format", []string{"format", "--file", missing}, exitUsage, "", "Error opening file " + missing},
+		{"unknown flag", []string{"--bogus"}, exitUsage, "", "flag provided but not defined: -bogus"},
+		{"no jobs", []string{"--jobs", "0", "--file", valid}, exitUsage, "", "--jobs must be at least 1"},
+		{"format of two files", []string{"format", "--file", valid, "--file", valid}, exitUsage, "",
+			"format reads a single file but --file was given 2 times"},
+		{"format", []string{"format", `{"a":1}`}, exitValid, "{\n  \"a\": 1\n}\n", ""},
+	}
+	for _, test := range tests {
+		code, stdout, stderr := runCaptured(t, test.args...)
+		if code != test.code {
+			t.Errorf("%s: Expected exit code %d, Got : %d", test.name, test.code, code)
+		}
+		if stdout != test.stdout {
+			t.Errorf("%s: Expected stdout %q, Got : %q", test.name, test.stdout, stdout)
+		}
+		// errors only ever go to stderr, and nothing is written there on success
+		if test.stderr == "" && stderr != "" || !strings.Contains(stderr, test.stderr) {
+			t.Errorf("%s: Expected stderr to contain %q, Got : %q", test.name, test.stderr, stderr)
+		}
+	}
+}
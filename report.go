@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"json-parser/jsonparser"
+)
+
+var outputFormats = map[string]func(w io.Writer, results []fileResult) error{
+	"text":  writeTextSummary,
+	"json":  writeJsonReport,
+	"sarif": writeSarifReport,
+	"junit": writeJunitReport,
+}
+
+// The outcome of validating one input. err is nil if the input is valid, warnings is nil
+// if nothing was found that doesn't make it invalid
+type fileResult struct {
+	file     string
+	err      error
+	warnings error
+}
+
+// Prints the outcome of validating one input the way the text format shows it
+func printTextResult(r fileResult, color bool) {
+	if r.warnings != nil {
+		printError(r.warnings, color)
+	}
+	if r.err == nil {
+		fmt.Printf("%s: valid\n", r.file)
+	} else {
+		printError(r.err, color)
+	}
+}
+
+type summary struct {
+	Files      int `json:"files"`
+	Valid      int `json:"valid"`
+	Invalid    int `json:"invalid"`
+	Unreadable int `json:"unreadable"`
+}
+
+func summarize(results []fileResult) summary {
+	s := summary{Files: len(results)}
+	for _, r := range results {
+		switch exitCode(r.err) {
+		case exitValid:
+			s.Valid++
+		case exitInvalid:
+			s.Invalid++
+		default:
+			s.Unreadable++
+		}
+	}
+	return s
+}
+
+// The details of an error that reports show as separate fields
+type errorDetails struct {
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+// Returns the details of each error in err
+func describe(err error) []errorDetails {
+	if syntaxErrs := syntaxErrors(err); len(syntaxErrs) > 0 {
+		details := make([]errorDetails, len(syntaxErrs))
+		for i, syntaxErr := range syntaxErrs {
+			details[i] = errorDetails{Line: syntaxErr.Pos.Line, Column: syntaxErr.Pos.Column, Message: syntaxErr.Msg, Code: syntaxErr.Code.String()}
+		}
+		return details
+	}
+	if exitCode(err) == exitUsage {
+		return []errorDetails{{Message: err.Error(), Code: "ReadError"}}
+	}
+	return []errorDetails{{Message: err.Error(), Code: "Error"}}
+}
+
+func writeTextSummary(w io.Writer, results []fileResult) error {
+	if len(results) < 2 {
+		return nil
+	}
+	s := summarize(results)
+	fmt.Fprintf(w, "Checked %d files: %d valid, %d invalid", s.Files, s.Valid, s.Invalid)
+	if s.Unreadable > 0 {
+		fmt.Fprintf(w, ", %d could not be read", s.Unreadable)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func writeJsonReport(w io.Writer, results []fileResult) error {
+	type file struct {
+		File     string         `json:"file"`
+		Valid    bool           `json:"valid"`
+		Errors   []errorDetails `json:"errors,omitempty"`
+		Warnings []errorDetails `json:"warnings,omitempty"`
+	}
+	report := struct {
+		Files   []file  `json:"files"`
+		Summary summary `json:"summary"`
+	}{Files: []file{}, Summary: summarize(results)}
+	for _, r := range results {
+		f := file{File: r.file, Valid: r.err == nil}
+		if r.err != nil {
+			f.Errors = describe(r.err)
+		}
+		if r.warnings != nil {
+			f.Warnings = describe(r.warnings)
+		}
+		report.Files = append(report.Files, f)
+	}
+	return writeJson(w, report)
+}
+
+// Writes a SARIF 2.1.0 log with a result for each error and warning.
+// Columns are counted in bytes like the rest of the errors
+func writeSarifReport(w io.Writer, results []fileResult) error {
+	type region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+	}
+	type physicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *region `json:"region,omitempty"`
+	}
+	type location struct {
+		PhysicalLocation physicalLocation `json:"physicalLocation"`
+	}
+	type message struct {
+		Text string `json:"text"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+	type rule struct {
+		ID string `json:"id"`
+	}
+	type driver struct {
+		Name           string `json:"name"`
+		InformationURI string `json:"informationUri"`
+		Rules          []rule `json:"rules"`
+	}
+	type run struct {
+		Tool struct {
+			Driver driver `json:"driver"`
+		} `json:"tool"`
+		Results []result `json:"results"`
+	}
+
+	var sarifRun run
+	sarifRun.Tool.Driver = driver{Name: "jsonparse", InformationURI: "https://github.com/chubi-x/json-parser", Rules: []rule{}}
+	sarifRun.Results = []result{}
+	seenRules := map[string]bool{}
+	addResults := func(file string, err error, level string) {
+		for _, details := range describe(err) {
+			if !seenRules[details.Code] {
+				seenRules[details.Code] = true
+				sarifRun.Tool.Driver.Rules = append(sarifRun.Tool.Driver.Rules, rule{details.Code})
+			}
+			var loc location
+			loc.PhysicalLocation.ArtifactLocation.URI = file
+			if details.Line > 0 {
+				loc.PhysicalLocation.Region = &region{details.Line, details.Column}
+			}
+			sarifRun.Results = append(sarifRun.Results, result{
+				RuleID:    details.Code,
+				Level:     level,
+				Message:   message{details.Message},
+				Locations: []location{loc},
+			})
+		}
+	}
+	for _, r := range results {
+		if r.warnings != nil {
+			addResults(r.file, r.warnings, "warning")
+		}
+		if r.err != nil {
+			addResults(r.file, r.err, "error")
+		}
+	}
+	return writeJson(w, struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []run  `json:"runs"`
+	}{"https://json.schemastore.org/sarif-2.1.0.json", "2.1.0", []run{sarifRun}})
+}
+
+// Writes a JUnit XML report with a test case for each input. Invalid inputs are failures
+// and inputs that couldn't be read are errors
+func writeJunitReport(w io.Writer, results []fileResult) error {
+	type problem struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type testCase struct {
+		Name      string   `xml:"name,attr"`
+		ClassName string   `xml:"classname,attr"`
+		Failure   *problem `xml:"failure,omitempty"`
+		Error     *problem `xml:"error,omitempty"`
+	}
+	type testSuite struct {
+		Name      string     `xml:"name,attr"`
+		Tests     int        `xml:"tests,attr"`
+		Failures  int        `xml:"failures,attr"`
+		Errors    int        `xml:"errors,attr"`
+		TestCases []testCase `xml:"testcase"`
+	}
+	s := summarize(results)
+	suite := testSuite{Name: "jsonparse validate", Tests: s.Files, Failures: s.Invalid, Errors: s.Unreadable}
+	for _, r := range results {
+		c := testCase{Name: r.file, ClassName: "jsonparse.validate"}
+		if r.err != nil {
+			// the first error goes in the attributes, the text has all of them
+			details := describe(r.err)[0]
+			p := &problem{Message: details.Message, Type: details.Code, Text: r.err.Error()}
+			if exitCode(r.err) == exitUsage {
+				c.Error = p
+			} else {
+				c.Failure = p
+			}
+		}
+		suite.TestCases = append(suite.TestCases, c)
+	}
+	report := struct {
+		XMLName  xml.Name  `xml:"testsuites"`
+		Tests    int       `xml:"tests,attr"`
+		Failures int       `xml:"failures,attr"`
+		Errors   int       `xml:"errors,attr"`
+		Suite    testSuite `xml:"testsuite"`
+	}{Tests: s.Files, Failures: s.Invalid, Errors: s.Unreadable, Suite: suite}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func writeJson(w io.Writer, v any) error {
+	data, err := jsonparser.MarshalIndent(v, "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
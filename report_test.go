@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"json-parser/jsonparser"
+)
+
+// One valid input, one with a syntax error, one that couldn't be read and one with a warning
+func reportResults(t *testing.T) []fileResult {
+	err := jsonparser.Validate(strings.NewReader("{\n  \"a\": 1,\n}"))
+	if err == nil {
+		t.Fatal("Expected invalid but got valid")
+	}
+	var warnings jsonparser.ErrorList
+	options := jsonparser.Options{DuplicateKeys: jsonparser.WarnDuplicateKeys, Warnings: &warnings}
+	if err := options.Validate(strings.NewReader(`{"k": 1, "k": 2}`)); err != nil || len(warnings) != 1 {
+		t.Fatalf("Expected one warning, Got : %v %v", warnings, err)
+	}
+	return []fileResult{
+		{file: "valid.json"},
+		{file: "bad.json", err: inputError{name: "bad.json", err: err}},
+		{file: "missing.json", err: usageError{&fs.PathError{Op: "open", Path: "missing.json", Err: fs.ErrNotExist}}},
+		{file: "dup.json", warnings: inputError{name: "dup.json", err: warnings}},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+
+	expected := summary{Files: 4, Valid: 2, Invalid: 1, Unreadable: 1}
+	if s := summarize(reportResults(t)); s != expected {
+		t.Errorf("Expected %+v, Got : %+v", expected, s)
+	}
+}
+func TestDescribe(t *testing.T) {
+
+	results := reportResults(t)
+	tests := []struct {
+		err      error
+		expected errorDetails
+	}{
+		{results[1].err, errorDetails{Line: 3, Column: 1, Message: "Expected string but got }", Code: "TrailingComma"}},
+		{results[2].err, errorDetails{Message: "open missing.json: file does not exist", Code: "ReadError"}},
+		{results[3].warnings, errorDetails{Line: 1, Column: 10, Message: `Duplicate key "k", first defined at 1:2`, Code: "DuplicateKey"}},
+		{errors.New("broken pipe"), errorDetails{Message: "broken pipe", Code: "Error"}},
+	}
+	for _, test := range tests {
+		details := describe(test.err)
+		if len(details) != 1 || details[0] != test.expected {
+			t.Errorf("Expected %+v, Got : %+v", test.expected, details)
+		}
+	}
+}
+func TestJsonReport(t *testing.T) {
+
+	var out bytes.Buffer
+	if err := writeJsonReport(&out, reportResults(t)); err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Files []struct {
+			File     string         `json:"file"`
+			Valid    bool           `json:"valid"`
+			Errors   []errorDetails `json:"errors"`
+			Warnings []errorDetails `json:"warnings"`
+		} `json:"files"`
+		Summary summary `json:"summary"`
+	}
+	if err := jsonparser.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("Expected the report to parse, Got : %s\n%s", err, out.String())
+	}
+	if len(report.Files) != 4 || report.Summary != (summary{Files: 4, Valid: 2, Invalid: 1, Unreadable: 1}) {
+		t.Fatalf("Expected 4 files and their summary, Got : %+v", report)
+	}
+	valid, bad, missing, dup := report.Files[0], report.Files[1], report.Files[2], report.Files[3]
+	if valid.File != "valid.json" || !valid.Valid || valid.Errors != nil || valid.Warnings != nil {
+		t.Errorf("Expected valid.json to be valid, Got : %+v", valid)
+	}
+	if bad.Valid || len(bad.Errors) != 1 || bad.Errors[0].Line != 3 || bad.Errors[0].Column != 1 || bad.Errors[0].Code != "TrailingComma" {
+		t.Errorf("Expected a TrailingComma error at 3:1, Got : %+v", bad)
+	}
+	if missing.Valid || len(missing.Errors) != 1 || missing.Errors[0].Code != "ReadError" || missing.Errors[0].Line != 0 {
+		t.Errorf("Expected a ReadError without a position, Got : %+v", missing)
+	}
+	if !dup.Valid || dup.Errors != nil || len(dup.Warnings) != 1 || dup.Warnings[0].Code != "DuplicateKey" {
+		t.Errorf("Expected a valid file with a DuplicateKey warning, Got : %+v", dup)
+	}
+	// line and column are left out rather than written as 0
+	if strings.Count(out.String(), `"line"`) != 2 {
+		t.Errorf("Expected line only for the syntax error and the warning, Got : %s", out.String())
+	}
+}
+func TestSarifReport(t *testing.T) {
+
+	var out bytes.Buffer
+	if err := writeSarifReport(&out, reportResults(t)); err != nil {
+		t.Fatal(err)
+	}
+	type result struct {
+		RuleID  string `json:"ruleId"`
+		Level   string `json:"level"`
+		Message struct {
+			Text string `json:"text"`
+		} `json:"message"`
+		Locations []struct {
+			PhysicalLocation struct {
+				ArtifactLocation struct {
+					URI string `json:"uri"`
+				} `json:"artifactLocation"`
+				Region *struct {
+					StartLine   int `json:"startLine"`
+					StartColumn int `json:"startColumn"`
+				} `json:"region"`
+			} `json:"physicalLocation"`
+		} `json:"locations"`
+	}
+	var log struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []result `json:"results"`
+		} `json:"runs"`
+	}
+	if err := jsonparser.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("Expected the log to parse, Got : %s\n%s", err, out.String())
+	}
+	if log.Version != "2.1.0" || !strings.Contains(log.Schema, "sarif-2.1.0") || len(log.Runs) != 1 {
+		t.Fatalf("Expected a SARIF 2.1.0 log with one run, Got : %+v", log)
+	}
+	run := log.Runs[0]
+	rules := []string{}
+	for _, rule := range run.Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+	}
+	if run.Tool.Driver.Name != "jsonparse" || strings.Join(rules, " ") != "TrailingComma ReadError DuplicateKey" {
+		t.Errorf("Expected each code once as a rule, Got : %s %v", run.Tool.Driver.Name, rules)
+	}
+	expected := []struct {
+		uri, rule, level string
+		line, column     int
+	}{
+		{"bad.json", "TrailingComma", "error", 3, 1},
+		{"missing.json", "ReadError", "error", 0, 0},
+		{"dup.json", "DuplicateKey", "warning", 1, 10},
+	}
+	if len(run.Results) != len(expected) {
+		t.Fatalf("Expected %d results, Got : %+v", len(expected), run.Results)
+	}
+	for i, e := range expected {
+		r := run.Results[i]
+		if r.RuleID != e.rule || r.Level != e.level || r.Message.Text == "" || len(r.Locations) != 1 {
+			t.Errorf("Expected %s %s, Got : %+v", e.rule, e.level, r)
+			continue
+		}
+		location := r.Locations[0].PhysicalLocation
+		line, column := 0, 0
+		if location.Region != nil {
+			line, column = location.Region.StartLine, location.Region.StartColumn
+		}
+		if location.ArtifactLocation.URI != e.uri || line != e.line || column != e.column {
+			t.Errorf("Expected %s:%d:%d, Got : %s:%d:%d", e.uri, e.line, e.column, location.ArtifactLocation.URI, line, column)
+		}
+	}
+}
+func TestJunitReport(t *testing.T) {
+
+	var out bytes.Buffer
+	if err := writeJunitReport(&out, reportResults(t)); err != nil {
+		t.Fatal(err)
+	}
+	type problem struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+	var report struct {
+		XMLName  xml.Name `xml:"testsuites"`
+		Tests    int      `xml:"tests,attr"`
+		Failures int      `xml:"failures,attr"`
+		Errors   int      `xml:"errors,attr"`
+		Suite    struct {
+			Name      string `xml:"name,attr"`
+			Tests     int    `xml:"tests,attr"`
+			Failures  int    `xml:"failures,attr"`
+			Errors    int    `xml:"errors,attr"`
+			TestCases []struct {
+				Name    string   `xml:"name,attr"`
+				Failure *problem `xml:"failure"`
+				Error   *problem `xml:"error"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if !strings.HasPrefix(out.String(), "<?xml") {
+		t.Errorf("Expected an XML header, Got : %s", out.String())
+	}
+	if err := xml.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("Expected the report to parse, Got : %s\n%s", err, out.String())
+	}
+	suite := report.Suite
+	if report.Tests != 4 || report.Failures != 1 || report.Errors != 1 || suite.Tests != 4 || suite.Failures != 1 || suite.Errors != 1 {
+		t.Errorf("Expected 4 tests, 1 failure and 1 error, Got : %+v", report)
+	}
+	if len(suite.TestCases) != 4 {
+		t.Fatalf("Expected 4 test cases, Got : %+v", suite.TestCases)
+	}
+	for _, i := range []int{0, 3} {
+		if c := suite.TestCases[i]; c.Failure != nil || c.Error != nil {
+			t.Errorf("Expected %s to pass, Got : %+v", c.Name, c)
+		}
+	}
+	bad := suite.TestCases[1]
+	if bad.Name != "bad.json" || bad.Error != nil || bad.Failure == nil || bad.Failure.Type != "TrailingComma" ||
+		bad.Failure.Message != "Expected string but got }" || !strings.Contains(bad.Failure.Text, "bad.json:3:1:") {
+		t.Errorf("Expected a TrailingComma failure for bad.json, Got : %+v", bad)
+	}
+	missing := suite.TestCases[2]
+	if missing.Name != "missing.json" || missing.Failure != nil || missing.Error == nil || missing.Error.Type != "ReadError" {
+		t.Errorf("Expected a ReadError error for missing.json, Got : %+v", missing)
+	}
+}